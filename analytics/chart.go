@@ -2,12 +2,17 @@ package analytics
 
 import (
 	"fmt"
+	"html"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/go-echarts/go-echarts/v2/charts"
 	"github.com/go-echarts/go-echarts/v2/components"
 	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/mtnmunuklu/analyze-tags/attack"
+	"github.com/mtnmunuklu/analyze-tags/model"
 )
 
 type ChartType string
@@ -25,13 +30,17 @@ const (
 	TreemapChart   ChartType = "treemap"
 	GraphChart     ChartType = "graph"
 	TreeChart      ChartType = "tree"
+	MatrixChart    ChartType = "matrix"
 )
 
 type ChartParams struct {
 	Type   ChartType
-	Data   map[string][]string
+	Data   []model.Rule
 	Title  string
 	Output string
+
+	// Attack is the ATT&CK matrix drawn by the matrix chart.
+	Attack *attack.Matrix
 }
 
 func renderChartToFile(chart components.Charter, outputPath string) error {
@@ -48,6 +57,24 @@ func renderChartToFile(chart components.Charter, outputPath string) error {
 	return nil
 }
 
+// ruleLabels returns the display label of every rule. Titles shared by
+// several rules get the rule location appended so the rules stay distinct.
+func ruleLabels(rules []model.Rule) []string {
+	titleCounts := make(map[string]int)
+	for _, rule := range rules {
+		titleCounts[rule.Title]++
+	}
+
+	labels := make([]string, len(rules))
+	for i, rule := range rules {
+		labels[i] = rule.Title
+		if titleCounts[rule.Title] > 1 {
+			labels[i] = fmt.Sprintf("%s (%s)", rule.Title, rule.Location())
+		}
+	}
+	return labels
+}
+
 func FindChartType(chart string) (ChartType, error) {
 	switch chart {
 	case "bar":
@@ -74,6 +101,8 @@ func FindChartType(chart string) (ChartType, error) {
 		return GraphChart, nil
 	case "tree":
 		return TreeChart, nil
+	case "matrix":
+		return MatrixChart, nil
 	default:
 		return "", fmt.Errorf("unsupported chart type: %s", chart)
 	}
@@ -105,6 +134,8 @@ func GenerateChart(params ChartParams) (ChartGenerator, error) {
 		return &GraphChartGenerator{}, nil
 	case TreeChart:
 		return &TreeChartGenerator{}, nil
+	case MatrixChart:
+		return &MatrixChartGenerator{}, nil
 	default:
 		return nil, fmt.Errorf("unsupported chart type: %s", params.Type)
 	}
@@ -125,8 +156,8 @@ func (bcg *BarChartGenerator) Generate(params ChartParams) error {
 	)
 
 	tagCounts := make(map[string]int)
-	for _, tags := range params.Data {
-		for _, tag := range tags {
+	for _, rule := range params.Data {
+		for _, tag := range rule.Tags {
 			tagCounts[tag]++
 		}
 	}
@@ -164,8 +195,8 @@ func (lcg *LineChartGenerator) Generate(params ChartParams) error {
 	)
 
 	tagCounts := make(map[string]int)
-	for _, tags := range params.Data {
-		for _, tag := range tags {
+	for _, rule := range params.Data {
+		for _, tag := range rule.Tags {
 			tagCounts[tag]++
 		}
 	}
@@ -194,8 +225,8 @@ func (spg *ScatterPlotGenerator) Generate(params ChartParams) error {
 	)
 
 	tagCounts := make(map[string]int)
-	for _, tags := range params.Data {
-		for _, tag := range tags {
+	for _, rule := range params.Data {
+		for _, tag := range rule.Tags {
 			tagCounts[tag]++
 		}
 	}
@@ -224,8 +255,8 @@ func (pcg *PieChartGenerator) Generate(params ChartParams) error {
 
 	tagCounts := make(map[string]int)
 	totalCount := 0
-	for _, tags := range params.Data {
-		for _, tag := range tags {
+	for _, rule := range params.Data {
+		for _, tag := range rule.Tags {
 			tagCounts[tag]++
 			totalCount++
 		}
@@ -255,8 +286,8 @@ func (bpg *BoxPlotGenerator) Generate(params ChartParams) error {
 	)
 
 	tagCounts := make(map[string]int)
-	for _, tags := range params.Data {
-		for _, tag := range tags {
+	for _, rule := range params.Data {
+		for _, tag := range rule.Tags {
 			tagCounts[tag]++
 		}
 	}
@@ -291,29 +322,38 @@ func (hmg *HeatmapGenerator) Generate(params ChartParams) error {
 			Type:      "category",
 			SplitArea: &opts.SplitArea{Show: true},
 		}),
+	)
+
+	tagCounts := make(map[string]int)
+	maxCount := 1
+	for _, rule := range params.Data {
+		for _, tag := range rule.Tags {
+			tagCounts[tag]++
+			if tagCounts[tag] > maxCount {
+				maxCount = tagCounts[tag]
+			}
+		}
+	}
+
+	heatmap.SetGlobalOptions(
 		charts.WithVisualMapOpts(opts.VisualMap{
 			Calculable: true,
 			Min:        0,
-			Max:        10,
+			Max:        float32(maxCount),
 			InRange: &opts.VisualMapInRange{
 				Color: []string{"#50a3ba", "#eac736", "#d94e5d"},
 			},
 		}),
 	)
 
-	tagCounts := make(map[string]int)
-	for _, tags := range params.Data {
-		for _, tag := range tags {
-			tagCounts[tag]++
-		}
-	}
+	labels := ruleLabels(params.Data)
 
 	var data []opts.HeatMapData
-	for rule, tags := range params.Data {
-		for _, tag := range tags {
+	for i, rule := range params.Data {
+		for _, tag := range rule.Tags {
 
 			count := tagCounts[tag]
-			data = append(data, opts.HeatMapData{Value: [3]interface{}{tag, rule, count}})
+			data = append(data, opts.HeatMapData{Value: [3]interface{}{tag, labels[i], count}})
 		}
 	}
 
@@ -333,14 +373,14 @@ func (rcg *RadarChartGenerator) Generate(params ChartParams) error {
 	)
 
 	indicators := make([]*opts.Indicator, 0)
-	for rule := range params.Data {
-		indicators = append(indicators, &opts.Indicator{Name: rule})
+	for _, label := range ruleLabels(params.Data) {
+		indicators = append(indicators, &opts.Indicator{Name: label})
 	}
 
 	seriesData := make([]opts.RadarData, 0)
-	for _, tags := range params.Data {
+	for _, rule := range params.Data {
 		var rowData []interface{}
-		for _, tag := range tags {
+		for _, tag := range rule.Tags {
 			rowData = append(rowData, tag)
 		}
 		seriesData = append(seriesData, opts.RadarData{Value: rowData})
@@ -367,8 +407,8 @@ func (fcg *FunnelChartGenerator) Generate(params ChartParams) error {
 
 	var data []opts.FunnelData
 	tagCounts := make(map[string]int)
-	for _, tags := range params.Data {
-		for _, tag := range tags {
+	for _, rule := range params.Data {
+		for _, tag := range rule.Tags {
 			tagCounts[tag]++
 		}
 	}
@@ -394,18 +434,19 @@ func (wcg *WordCloudChartGenerator) Generate(params ChartParams) error {
 
 	tagCounts := make(map[string]int)
 	ruleTagCounts := make(map[string]int)
-	for rule, tags := range params.Data {
-		for _, tag := range tags {
+	labels := ruleLabels(params.Data)
+	for i, rule := range params.Data {
+		for _, tag := range rule.Tags {
 			tagCounts[tag]++
-			ruleTagCounts[rule]++
+			ruleTagCounts[labels[i]]++
 		}
 	}
 
 	var data []opts.WordCloudData
 	processedTags := make(map[string]bool)
-	for rule, tags := range params.Data {
-		ruleWeight := float32(ruleTagCounts[rule])
-		for _, tag := range tags {
+	for i, rule := range params.Data {
+		ruleWeight := float32(ruleTagCounts[labels[i]])
+		for _, tag := range rule.Tags {
 
 			if _, ok := processedTags[tag]; ok {
 				continue
@@ -416,7 +457,7 @@ func (wcg *WordCloudChartGenerator) Generate(params ChartParams) error {
 			processedTags[tag] = true
 		}
 
-		data = append(data, opts.WordCloudData{Name: rule, Value: ruleWeight})
+		data = append(data, opts.WordCloudData{Name: labels[i], Value: ruleWeight})
 	}
 
 	wordCloud.AddSeries("Data", data)
@@ -434,14 +475,16 @@ func (tcg *TreemapChartGenerator) Generate(params ChartParams) error {
 		}),
 	)
 
+	labels := ruleLabels(params.Data)
+
 	var treemapData []opts.TreeMapNode
-	for ruleName, tags := range params.Data {
-		for _, val := range tags {
+	for i, rule := range params.Data {
+		for _, val := range rule.Tags {
 			intValue, err := strconv.Atoi(val)
 			if err != nil {
 				return err
 			}
-			treemapData = append(treemapData, opts.TreeMapNode{Name: ruleName, Value: intValue})
+			treemapData = append(treemapData, opts.TreeMapNode{Name: labels[i], Value: intValue})
 		}
 	}
 
@@ -464,8 +507,11 @@ func (gcg *GraphChartGenerator) Generate(params ChartParams) error {
 	nodes := make([]opts.GraphNode, 0)
 	links := make([]opts.GraphLink, 0)
 
+	labels := ruleLabels(params.Data)
+
 	added := make(map[string]bool)
-	for ruleName, tags := range params.Data {
+	for i, rule := range params.Data {
+		ruleName := labels[i]
 
 		if !added[ruleName] {
 
@@ -474,7 +520,7 @@ func (gcg *GraphChartGenerator) Generate(params ChartParams) error {
 			added[ruleName] = true
 		}
 
-		for _, tag := range tags {
+		for _, tag := range rule.Tags {
 			if tag != ruleName {
 				links = append(links, opts.GraphLink{Source: ruleName, Target: tag})
 			}
@@ -505,16 +551,16 @@ func (tcg *TreeChartGenerator) Generate(params ChartParams) error {
 	)
 
 	tagWeights := make(map[string]int)
-	for _, tags := range params.Data {
-		for _, tag := range tags {
+	for _, rule := range params.Data {
+		for _, tag := range rule.Tags {
 			tagWeights[tag] += 1
 		}
 	}
 
 	var treeData []opts.TreeData
 	added := make(map[string]bool)
-	for _, tags := range params.Data {
-		for _, tag := range tags {
+	for _, rule := range params.Data {
+		for _, tag := range rule.Tags {
 			if !added[tag] {
 				weight := tagWeights[tag]
 				treeData = append(treeData, opts.TreeData{Name: tag, Value: weight})
@@ -527,3 +573,160 @@ func (tcg *TreeChartGenerator) Generate(params ChartParams) error {
 
 	return renderChartToFile(tree, params.Output)
 }
+
+// MatrixChartGenerator draws the ATT&CK matrix as a heatmap with a column per
+// tactic and a cell per technique, colored by the number of rules tagged
+// with the technique or one of its sub-techniques. The tooltip of a cell
+// lists the covered sub-techniques and the rules.
+type MatrixChartGenerator struct{}
+
+func (mcg *MatrixChartGenerator) Generate(params ChartParams) error {
+	if params.Attack == nil {
+		return fmt.Errorf("the matrix chart needs an ATT&CK bundle")
+	}
+	matrix := params.Attack
+
+	labels := ruleLabels(params.Data)
+	techniqueRules := make(map[string][]string)
+	subtechniqueRules := make(map[string][]string)
+	for i, rule := range params.Data {
+		seen := make(map[string]bool)
+		for _, id := range matrix.TechniqueIDs(rule.Tags) {
+			technique, ok := matrix.Technique(id)
+			if !ok {
+				continue
+			}
+			if technique.IsSubtechnique() {
+				subtechniqueRules[id] = append(subtechniqueRules[id], labels[i])
+			}
+			if parent := technique.Parent(); !seen[parent] {
+				techniqueRules[parent] = append(techniqueRules[parent], labels[i])
+				seen[parent] = true
+			}
+		}
+	}
+
+	// Cells are drawn from the top of each column, so the first technique
+	// of a tactic gets the highest row of the category axis.
+	tactics := matrix.Tactics()
+	columns := make([][]attack.Technique, len(tactics))
+	rows := 0
+	for x, tactic := range tactics {
+		columns[x] = matrixTechniques(matrix, tactic.ShortName)
+		if len(columns[x]) > rows {
+			rows = len(columns[x])
+		}
+	}
+
+	var tacticNames []string
+	var data []opts.HeatMapData
+	maxCount := 1
+	for x, tactic := range tactics {
+		tacticNames = append(tacticNames, tactic.Name)
+
+		for y, technique := range columns[x] {
+			count := len(techniqueRules[technique.ID])
+			if count > maxCount {
+				maxCount = count
+			}
+
+			// The tooltip travels as a fourth value so that the formatter
+			// does not have to embed the rule titles in JavaScript.
+			tooltip := matrixTooltip(matrix, technique, techniqueRules[technique.ID], subtechniqueRules)
+			data = append(data, opts.HeatMapData{Name: technique.ID, Value: [4]interface{}{x, rows - 1 - y, count, tooltip}})
+		}
+	}
+
+	yAxisData := make([]string, rows)
+	for i := range yAxisData {
+		yAxisData[i] = strconv.Itoa(rows - i)
+	}
+
+	heatmap := charts.NewHeatMap()
+	heatmap.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			Width:  fmt.Sprintf("%dpx", 140*len(tacticNames)+160),
+			Height: fmt.Sprintf("%dpx", 28*rows+200),
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title: params.Title,
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:      true,
+			Formatter: opts.FuncOpts("function (params) { return params.value[3]; }"),
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Type:      "category",
+			Data:      tacticNames,
+			SplitArea: &opts.SplitArea{Show: true},
+			AxisLabel: &opts.AxisLabel{Show: true, Interval: "0", Rotate: 30},
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Type: "category",
+			Data: yAxisData,
+		}),
+		charts.WithVisualMapOpts(opts.VisualMap{
+			Calculable: true,
+			Show:       true,
+			Dimension:  "2",
+			Min:        0,
+			Max:        float32(maxCount),
+			InRange: &opts.VisualMapInRange{
+				Color: DefaultNavigatorColors,
+			},
+		}),
+	)
+
+	heatmap.SetXAxis(tacticNames).
+		AddSeries("Rules", data,
+			charts.WithLabelOpts(opts.Label{
+				Show:      true,
+				Formatter: "{b}",
+			}))
+
+	return renderChartToFile(heatmap, params.Output)
+}
+
+// matrixTechniques returns the current techniques of a tactic sorted by name,
+// leaving out sub-techniques and revoked or deprecated techniques.
+func matrixTechniques(matrix *attack.Matrix, tactic string) []attack.Technique {
+	var techniques []attack.Technique
+	for _, technique := range matrix.Techniques() {
+		if technique.IsSubtechnique() || technique.Revoked || technique.Deprecated {
+			continue
+		}
+		for _, t := range technique.Tactics {
+			if t == tactic {
+				techniques = append(techniques, technique)
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(techniques, func(i, j int) bool {
+		return techniques[i].Name < techniques[j].Name
+	})
+	return techniques
+}
+
+func matrixTooltip(matrix *attack.Matrix, technique attack.Technique, rules []string, subtechniqueRules map[string][]string) string {
+	lines := []string{
+		fmt.Sprintf("<b>%s %s</b>", technique.ID, html.EscapeString(technique.Name)),
+		fmt.Sprintf("%d rules", len(rules)),
+	}
+
+	for _, subtechnique := range matrix.Techniques() {
+		if !subtechnique.IsSubtechnique() || subtechnique.Parent() != technique.ID {
+			continue
+		}
+		if count := len(subtechniqueRules[subtechnique.ID]); count > 0 {
+			lines = append(lines, fmt.Sprintf("%s %s: %d rules", subtechnique.ID, html.EscapeString(subtechnique.Name), count))
+		}
+	}
+
+	for _, rule := range rules {
+		lines = append(lines, "- "+html.EscapeString(rule))
+	}
+
+	return strings.Join(lines, "<br/>")
+}
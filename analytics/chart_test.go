@@ -5,12 +5,15 @@ import (
 	"testing"
 
 	"github.com/mtnmunuklu/analyze-tags/analytics"
+	"github.com/mtnmunuklu/analyze-tags/attack"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestChartGenerator_Generate(t *testing.T) {
 	params := analytics.ChartParams{
 		Type:   analytics.TreeChart,
-		Data:   map[string][]string{"Rule1": {"tag1", "tag3", "tag2"}, "Rule2": {"tag1", "tag2"}},
+		Data:   []model.Rule{{Title: "Rule1", Tags: []string{"tag1", "tag3", "tag2"}}, {Title: "Rule2", Tags: []string{"tag1", "tag2"}}},
 		Title:  "Tree Chart Test",
 		Output: "./data/output/test_tree_chart.html",
 	}
@@ -20,7 +23,9 @@ func TestChartGenerator_Generate(t *testing.T) {
 		t.Errorf("Error generating chart generator: %v", err)
 	}
 
-	if err := generator.Generate(params); err != nil {
+	err = generator.Generate(params)
+	defer os.Remove(params.Output)
+	if err != nil {
 		t.Errorf("Error generating chart: %v", err)
 	}
 
@@ -29,3 +34,79 @@ func TestChartGenerator_Generate(t *testing.T) {
 	}
 
 }
+
+func TestFilterTags(t *testing.T) {
+	rules := []model.Rule{
+		{Title: "Rule1", Tags: []string{"cve", "cve:CVE-2021-44228", "cve-year:2021", "cwe:CWE-502"}},
+		{Title: "Rule2", Tags: []string{"exposure", "cwe:CWE-200"}},
+		{Title: "Rule3", Tags: []string{"exposure"}},
+	}
+
+	filtered := analytics.FilterTags(rules, []string{"cwe"})
+	assert.Equal(t, []model.Rule{
+		{Title: "Rule1", Tags: []string{"cwe:CWE-502"}},
+		{Title: "Rule2", Tags: []string{"cwe:CWE-200"}},
+	}, filtered)
+
+	filtered = analytics.FilterTags(rules, []string{"cve-year", "cve"})
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, []string{"cve:CVE-2021-44228", "cve-year:2021"}, filtered[0].Tags)
+	assert.Equal(t, []string{"cve", "cve:CVE-2021-44228", "cve-year:2021", "cwe:CWE-502"}, rules[0].Tags)
+}
+
+func TestFieldTags(t *testing.T) {
+	rules := []model.Rule{
+		{Title: "Rule1", Tags: []string{"attack.t1059"}, Metadata: map[string][]string{"attack_tactic": {"Execution"}}},
+		{Title: "Rule2", Tags: []string{"container"}},
+	}
+
+	fielded := analytics.FieldTags(rules, "attack_tactic")
+	if assert.Len(t, fielded, 1) {
+		assert.Equal(t, []string{"Execution"}, fielded[0].Tags)
+	}
+	assert.Equal(t, []string{"attack.t1059"}, rules[0].Tags)
+}
+
+func TestMatrixChart(t *testing.T) {
+	matrix, err := attack.Load("../attack/data/enterprise-attack.json")
+	if err != nil {
+		t.Fatalf("error loading bundle: %v", err)
+	}
+
+	params := analytics.ChartParams{
+		Type: analytics.MatrixChart,
+		Data: []model.Rule{
+			{Title: "Rule1", Tags: []string{"attack.t1059.001", "attack.t1059.003"}},
+			{Title: "Rule2 <b>", Tags: []string{"attack.t1059"}},
+		},
+		Title:  "Matrix Chart Test",
+		Output: "./data/output/test/matrix_chart.html",
+		Attack: matrix,
+	}
+
+	generator, err := analytics.GenerateChart(params)
+	if err != nil {
+		t.Fatalf("Error generating chart generator: %v", err)
+	}
+
+	err = generator.Generate(params)
+	defer os.Remove(params.Output)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	contents, err := os.ReadFile(params.Output)
+	if err != nil {
+		t.Fatalf("Chart file was not created: %v", err)
+	}
+
+	// T1059 rolls up both rules, and the tooltip lists the sub-techniques
+	// and the escaped rule titles.
+	assert.Contains(t, string(contents), `"name":"T1059","value":[3,3,2,`)
+	assert.Contains(t, string(contents), `T1059.001 PowerShell: 1 rules`)
+	assert.Contains(t, string(contents), `- Rule2 &lt;b&gt;`)
+	assert.Contains(t, string(contents), `"Command and Control"`)
+
+	params.Attack = nil
+	assert.EqualError(t, generator.Generate(params), "the matrix chart needs an ATT&CK bundle")
+}
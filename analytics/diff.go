@@ -0,0 +1,324 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/xuri/excelize/v2"
+)
+
+type ChangeStatus string
+
+const (
+	Added   ChangeStatus = "added"
+	Removed ChangeStatus = "removed"
+	Changed ChangeStatus = "changed"
+)
+
+// RuleChange is a rule added, removed or changed between two rulesets.
+// Fields lists the changed fields of a changed rule other than its tags.
+type RuleChange struct {
+	Status   ChangeStatus
+	Key      string
+	Title    string
+	Location string
+	Gained   []string
+	Lost     []string
+	Fields   []string
+}
+
+// TagDelta is the number of rules tagged with a tag in both rulesets.
+type TagDelta struct {
+	Tag string
+	Old int
+	New int
+}
+
+func (d TagDelta) Delta() int {
+	return d.New - d.Old
+}
+
+// DiffParams compares two rulesets. Rules are matched by key, so rules
+// without an id only match when their paths are relative to the same root.
+type DiffParams struct {
+	Old []model.Rule
+	New []model.Rule
+}
+
+// ruleKeys returns the key of every rule, falling back to the location for
+// rules that share an id with an earlier rule.
+func ruleKeys(rules []model.Rule) ([]string, map[string]model.Rule) {
+	keys := make([]string, len(rules))
+	byKey := make(map[string]model.Rule, len(rules))
+	for i, rule := range rules {
+		key := rule.Key()
+		if _, ok := byKey[key]; ok {
+			key = rule.Location()
+		}
+		keys[i] = key
+		byKey[key] = rule
+	}
+	return keys, byKey
+}
+
+// RuleChanges returns the added and changed rules in the order of the new
+// ruleset, followed by the removed rules in the order of the old one.
+func (d *DiffParams) RuleChanges() []RuleChange {
+	oldKeys, oldRules := ruleKeys(d.Old)
+	newKeys, newRules := ruleKeys(d.New)
+
+	var changes []RuleChange
+	for _, key := range newKeys {
+		rule := newRules[key]
+		old, ok := oldRules[key]
+		if !ok {
+			changes = append(changes, RuleChange{
+				Status:   Added,
+				Key:      key,
+				Title:    rule.Title,
+				Location: rule.Location(),
+				Gained:   uniqueTags(rule.Tags),
+			})
+			continue
+		}
+
+		change := RuleChange{
+			Status:   Changed,
+			Key:      key,
+			Title:    rule.Title,
+			Location: rule.Location(),
+			Gained:   tagsMissingFrom(rule.Tags, old.Tags),
+			Lost:     tagsMissingFrom(old.Tags, rule.Tags),
+		}
+		if old.Title != rule.Title {
+			change.Fields = append(change.Fields, "title")
+		}
+		if old.Severity != rule.Severity {
+			change.Fields = append(change.Fields, "severity")
+		}
+		if old.Format != rule.Format {
+			change.Fields = append(change.Fields, "format")
+		}
+		if len(change.Gained) > 0 || len(change.Lost) > 0 || len(change.Fields) > 0 {
+			changes = append(changes, change)
+		}
+	}
+
+	for _, key := range oldKeys {
+		rule := oldRules[key]
+		if _, ok := newRules[key]; !ok {
+			changes = append(changes, RuleChange{
+				Status:   Removed,
+				Key:      key,
+				Title:    rule.Title,
+				Location: rule.Location(),
+				Lost:     uniqueTags(rule.Tags),
+			})
+		}
+	}
+
+	return changes
+}
+
+func uniqueTags(tags []string) []string {
+	return tagsMissingFrom(tags, nil)
+}
+
+// tagsMissingFrom returns the tags of a that are not in b, without
+// duplicates.
+func tagsMissingFrom(a, b []string) []string {
+	exclude := make(map[string]bool, len(b))
+	for _, tag := range b {
+		exclude[tag] = true
+	}
+
+	var missing []string
+	for _, tag := range a {
+		if !exclude[tag] {
+			missing = append(missing, tag)
+			exclude[tag] = true
+		}
+	}
+	return missing
+}
+
+// TagDeltas returns the tags whose number of rules differs between the two
+// rulesets, sorted by tag.
+func (d *DiffParams) TagDeltas() []TagDelta {
+	counts := make(map[string]*TagDelta)
+	count := func(rules []model.Rule, old bool) {
+		for _, rule := range rules {
+			for _, tag := range uniqueTags(rule.Tags) {
+				if counts[tag] == nil {
+					counts[tag] = &TagDelta{Tag: tag}
+				}
+				if old {
+					counts[tag].Old++
+				} else {
+					counts[tag].New++
+				}
+			}
+		}
+	}
+	count(d.Old, true)
+	count(d.New, false)
+
+	var deltas []TagDelta
+	for _, delta := range counts {
+		if delta.Delta() != 0 {
+			deltas = append(deltas, *delta)
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].Tag < deltas[j].Tag
+	})
+	return deltas
+}
+
+// ToExcel writes the rule changes, the tags gained and lost by every rule and
+// the per-tag count deltas.
+func (d *DiffParams) ToExcel(output string) error {
+	changes := d.RuleChanges()
+
+	file := excelize.NewFile()
+	sheetName := "Rule Changes"
+	index, err := file.NewSheet(sheetName)
+	if err != nil {
+		return err
+	}
+
+	file.SetCellValue(sheetName, "A1", "Status")
+	file.SetCellValue(sheetName, "B1", "Rule")
+	file.SetCellValue(sheetName, "C1", "ID")
+	file.SetCellValue(sheetName, "D1", "Location")
+	file.SetCellValue(sheetName, "E1", "Gained Tags")
+	file.SetCellValue(sheetName, "F1", "Lost Tags")
+	file.SetCellValue(sheetName, "G1", "Changed Fields")
+
+	for i, change := range changes {
+		row := i + 2
+		file.SetCellValue(sheetName, fmt.Sprintf("A%d", row), string(change.Status))
+		file.SetCellValue(sheetName, fmt.Sprintf("B%d", row), change.Title)
+		file.SetCellValue(sheetName, fmt.Sprintf("C%d", row), change.Key)
+		file.SetCellValue(sheetName, fmt.Sprintf("D%d", row), change.Location)
+		file.SetCellValue(sheetName, fmt.Sprintf("E%d", row), strings.Join(change.Gained, ", "))
+		file.SetCellValue(sheetName, fmt.Sprintf("F%d", row), strings.Join(change.Lost, ", "))
+		file.SetCellValue(sheetName, fmt.Sprintf("G%d", row), strings.Join(change.Fields, ", "))
+	}
+
+	if err := writeTagChangeSheet(file, changes); err != nil {
+		return err
+	}
+	if err := d.writeTagDeltaSheet(file); err != nil {
+		return err
+	}
+
+	file.SetActiveSheet(index)
+
+	return file.SaveAs(output)
+}
+
+func writeTagChangeSheet(file *excelize.File, changes []RuleChange) error {
+	sheetName := "Tag Changes"
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return err
+	}
+
+	file.SetCellValue(sheetName, "A1", "Rule")
+	file.SetCellValue(sheetName, "B1", "ID")
+	file.SetCellValue(sheetName, "C1", "Tag")
+	file.SetCellValue(sheetName, "D1", "Change")
+
+	row := 2
+	for _, change := range changes {
+		for _, tags := range []struct {
+			change string
+			tags   []string
+		}{{"gained", change.Gained}, {"lost", change.Lost}} {
+			for _, tag := range tags.tags {
+				file.SetCellValue(sheetName, fmt.Sprintf("A%d", row), change.Title)
+				file.SetCellValue(sheetName, fmt.Sprintf("B%d", row), change.Key)
+				file.SetCellValue(sheetName, fmt.Sprintf("C%d", row), tag)
+				file.SetCellValue(sheetName, fmt.Sprintf("D%d", row), tags.change)
+				row++
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *DiffParams) writeTagDeltaSheet(file *excelize.File) error {
+	sheetName := "Tag Deltas"
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return err
+	}
+
+	file.SetCellValue(sheetName, "A1", "Tag")
+	file.SetCellValue(sheetName, "B1", "Old Count")
+	file.SetCellValue(sheetName, "C1", "New Count")
+	file.SetCellValue(sheetName, "D1", "Delta")
+
+	for i, delta := range d.TagDeltas() {
+		row := i + 2
+		file.SetCellValue(sheetName, fmt.Sprintf("A%d", row), delta.Tag)
+		file.SetCellValue(sheetName, fmt.Sprintf("B%d", row), delta.Old)
+		file.SetCellValue(sheetName, fmt.Sprintf("C%d", row), delta.New)
+		file.SetCellValue(sheetName, fmt.Sprintf("D%d", row), delta.Delta())
+	}
+
+	return nil
+}
+
+// ToChart writes a bar chart of the per-tag count deltas, with the tags
+// gained in green and the tags lost in red.
+func (d *DiffParams) ToChart(output string) error {
+	changes := d.RuleChanges()
+	statusCounts := make(map[ChangeStatus]int)
+	for _, change := range changes {
+		statusCounts[change.Status]++
+	}
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title: "Tag count deltas",
+			Subtitle: fmt.Sprintf("%d rules added, %d removed, %d changed",
+				statusCounts[Added], statusCounts[Removed], statusCounts[Changed]),
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show: true,
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			AxisLabel: &opts.AxisLabel{Show: true, Interval: "0", Rotate: 30},
+		}),
+	)
+
+	var xAxisData []string
+	var seriesData []opts.BarData
+	for _, delta := range d.TagDeltas() {
+		color := "#91cc75"
+		if delta.Delta() < 0 {
+			color = "#ee6666"
+		}
+		xAxisData = append(xAxisData, delta.Tag)
+		seriesData = append(seriesData, opts.BarData{
+			Value:     delta.Delta(),
+			ItemStyle: &opts.ItemStyle{Color: color},
+		})
+	}
+
+	bar.SetXAxis(xAxisData).
+		AddSeries("Delta", seriesData,
+			charts.WithLabelOpts(opts.Label{
+				Show:      true,
+				Position:  "top",
+				Formatter: "{c}",
+			}))
+
+	return renderChartToFile(bar, output)
+}
@@ -0,0 +1,87 @@
+package analytics_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/analytics"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func diffParams() analytics.DiffParams {
+	return analytics.DiffParams{
+		Old: []model.Rule{
+			{ID: "1", Title: "Rule1", Path: "a.yml", Tags: []string{"tag1", "tag2"}},
+			{ID: "2", Title: "Rule2", Path: "a.yml", Index: 1, Tags: []string{"tag1"}},
+			{Title: "Rule3", Path: "b.yml", Tags: []string{"tag2", "tag3"}},
+			{ID: "4", Title: "Rule4", Path: "c.yml", Tags: []string{"tag1"}},
+		},
+		New: []model.Rule{
+			{ID: "1", Title: "Rule1", Path: "a.yml", Tags: []string{"tag2", "tag4", "tag4"}},
+			{ID: "2", Title: "Rule2", Path: "a.yml", Index: 1, Tags: []string{"tag1"}},
+			{Title: "Rule3", Path: "b.yml", Tags: []string{"tag2", "tag3"}, Severity: "high"},
+			{ID: "5", Title: "Rule5", Path: "c.yml", Tags: []string{"tag4"}},
+		},
+	}
+}
+
+func TestRuleChanges(t *testing.T) {
+	params := diffParams()
+
+	assert.Equal(t, []analytics.RuleChange{
+		{Status: analytics.Changed, Key: "1", Title: "Rule1", Location: "a.yml#0", Gained: []string{"tag4"}, Lost: []string{"tag1"}},
+		{Status: analytics.Changed, Key: "b.yml#0", Title: "Rule3", Location: "b.yml#0", Fields: []string{"severity"}},
+		{Status: analytics.Added, Key: "5", Title: "Rule5", Location: "c.yml#0", Gained: []string{"tag4"}},
+		{Status: analytics.Removed, Key: "4", Title: "Rule4", Location: "c.yml#0", Lost: []string{"tag1"}},
+	}, params.RuleChanges())
+
+	assert.Equal(t, []analytics.TagDelta{
+		{Tag: "tag1", Old: 3, New: 1},
+		{Tag: "tag4", Old: 0, New: 2},
+	}, params.TagDeltas())
+	assert.Equal(t, -2, params.TagDeltas()[0].Delta())
+}
+
+func TestDiffOutputs(t *testing.T) {
+	params := diffParams()
+
+	output := "./data/output/test/diff.xlsx"
+	err := params.ToExcel(output)
+	defer os.Remove(output)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	file, err := excelize.OpenFile(output)
+	if err != nil {
+		t.Fatalf("error opening output: %v", err)
+	}
+	defer file.Close()
+
+	rows, _ := file.GetRows("Tag Changes")
+	assert.Equal(t, [][]string{
+		{"Rule", "ID", "Tag", "Change"},
+		{"Rule1", "1", "tag4", "gained"},
+		{"Rule1", "1", "tag1", "lost"},
+		{"Rule5", "5", "tag4", "gained"},
+		{"Rule4", "4", "tag1", "lost"},
+	}, rows)
+	value, _ := file.GetCellValue("Tag Deltas", "D2")
+	assert.Equal(t, "-2", value)
+
+	chart := "./data/output/test/diff_chart.html"
+	err = params.ToChart(chart)
+	defer os.Remove(chart)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	contents, err := os.ReadFile(chart)
+	if err != nil {
+		t.Fatalf("Chart file was not created: %v", err)
+	}
+	assert.Contains(t, string(contents), `1 rules added, 1 removed, 2 changed`)
+	assert.Contains(t, string(contents), `{"value":-2,"itemStyle":{"color":"#ee6666"}}`)
+}
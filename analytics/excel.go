@@ -2,14 +2,23 @@ package analytics
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/mtnmunuklu/analyze-tags/attack"
+	"github.com/mtnmunuklu/analyze-tags/model"
 	"github.com/xuri/excelize/v2"
 )
 
 type ExcelParams struct {
 	SheetName string
-	Data      map[string][]string
+	Data      []model.Rule
 	Output    string
+	GroupBy   string
+
+	// Attack adds an ATT&CK sheet with the name, tactics and platforms of
+	// every technique a rule is tagged with.
+	Attack *attack.Matrix
 }
 
 func (e *ExcelParams) ToExcel() error {
@@ -21,16 +30,42 @@ func (e *ExcelParams) ToExcel() error {
 
 	file.SetCellValue(e.SheetName, "A1", "Rule")
 	file.SetCellValue(e.SheetName, "B1", "Tag")
+	file.SetCellValue(e.SheetName, "C1", "Format")
+	file.SetCellValue(e.SheetName, "D1", "Severity")
+	file.SetCellValue(e.SheetName, "E1", "Author")
+	file.SetCellValue(e.SheetName, "F1", "ID")
+	file.SetCellValue(e.SheetName, "G1", "Location")
 
 	row := 2
-	for rule, tags := range e.Data {
-		for _, tag := range tags {
-			file.SetCellValue(e.SheetName, fmt.Sprintf("A%d", row), rule)
+	for _, rule := range e.Data {
+		for _, tag := range rule.Tags {
+			file.SetCellValue(e.SheetName, fmt.Sprintf("A%d", row), rule.Title)
 			file.SetCellValue(e.SheetName, fmt.Sprintf("B%d", row), tag)
+			file.SetCellValue(e.SheetName, fmt.Sprintf("C%d", row), string(rule.Format))
+			file.SetCellValue(e.SheetName, fmt.Sprintf("D%d", row), rule.Severity)
+			file.SetCellValue(e.SheetName, fmt.Sprintf("E%d", row), rule.Author)
+			file.SetCellValue(e.SheetName, fmt.Sprintf("F%d", row), rule.Key())
+			file.SetCellValue(e.SheetName, fmt.Sprintf("G%d", row), rule.Location())
 			row++
 		}
 	}
 
+	if err := e.writeDuplicateSheet(file); err != nil {
+		return err
+	}
+
+	if e.GroupBy != "" {
+		if err := e.writeGroupSheet(file); err != nil {
+			return err
+		}
+	}
+
+	if e.Attack != nil {
+		if err := e.writeAttackSheet(file); err != nil {
+			return err
+		}
+	}
+
 	file.SetActiveSheet(index)
 
 	err = file.SaveAs(e.Output)
@@ -40,3 +75,124 @@ func (e *ExcelParams) ToExcel() error {
 
 	return nil
 }
+
+// groupSheetName returns the name of the sheet of a -groupBy field, with
+// the characters Excel does not allow in sheet names replaced and cut to the
+// length Excel allows.
+func groupSheetName(field string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '_'
+		}
+		return r
+	}, "By "+field)
+
+	if runes := []rune(name); len(runes) > excelize.MaxSheetNameLength {
+		name = string(runes[:excelize.MaxSheetNameLength])
+	}
+	return strings.TrimRight(name, "'")
+}
+
+func (e *ExcelParams) writeGroupSheet(file *excelize.File) error {
+	sheetName := groupSheetName(e.GroupBy)
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return err
+	}
+
+	file.SetCellValue(sheetName, "A1", e.GroupBy)
+	file.SetCellValue(sheetName, "B1", "Tag")
+	file.SetCellValue(sheetName, "C1", "Count")
+
+	groups := GroupRules(e.Data, e.GroupBy)
+
+	row := 2
+	for _, group := range sortedGroupNames(groups) {
+		tagCounts := make(map[string]int)
+		for _, rule := range groups[group] {
+			for _, tag := range rule.Tags {
+				tagCounts[tag]++
+			}
+		}
+
+		tags := make([]string, 0, len(tagCounts))
+		for tag := range tagCounts {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+
+		for _, tag := range tags {
+			file.SetCellValue(sheetName, fmt.Sprintf("A%d", row), group)
+			file.SetCellValue(sheetName, fmt.Sprintf("B%d", row), tag)
+			file.SetCellValue(sheetName, fmt.Sprintf("C%d", row), tagCounts[tag])
+			row++
+		}
+	}
+
+	return nil
+}
+
+func (e *ExcelParams) writeDuplicateSheet(file *excelize.File) error {
+	duplicates := model.DuplicateTitles(e.Data)
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	sheetName := "Duplicate Titles"
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return err
+	}
+
+	file.SetCellValue(sheetName, "A1", "Title")
+	file.SetCellValue(sheetName, "B1", "ID")
+	file.SetCellValue(sheetName, "C1", "Location")
+
+	row := 2
+	for _, duplicate := range duplicates {
+		for _, rule := range duplicate.Rules {
+			file.SetCellValue(sheetName, fmt.Sprintf("A%d", row), duplicate.Title)
+			file.SetCellValue(sheetName, fmt.Sprintf("B%d", row), rule.Key())
+			file.SetCellValue(sheetName, fmt.Sprintf("C%d", row), rule.Location())
+			row++
+		}
+	}
+
+	return nil
+}
+
+func (e *ExcelParams) writeAttackSheet(file *excelize.File) error {
+	sheetName := "ATT&CK"
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return err
+	}
+
+	file.SetCellValue(sheetName, "A1", "Rule")
+	file.SetCellValue(sheetName, "B1", "Technique ID")
+	file.SetCellValue(sheetName, "C1", "Technique")
+	file.SetCellValue(sheetName, "D1", "Tactics")
+	file.SetCellValue(sheetName, "E1", "Platforms")
+	file.SetCellValue(sheetName, "F1", "Location")
+
+	row := 2
+	for _, rule := range e.Data {
+		for _, id := range e.Attack.TechniqueIDs(rule.Tags) {
+			technique, _ := e.Attack.Technique(id)
+
+			var tactics []string
+			for _, shortName := range technique.Tactics {
+				if tactic, ok := e.Attack.Tactic(shortName); ok {
+					tactics = append(tactics, tactic.Name)
+				}
+			}
+
+			file.SetCellValue(sheetName, fmt.Sprintf("A%d", row), rule.Title)
+			file.SetCellValue(sheetName, fmt.Sprintf("B%d", row), id)
+			file.SetCellValue(sheetName, fmt.Sprintf("C%d", row), e.Attack.FullName(id))
+			file.SetCellValue(sheetName, fmt.Sprintf("D%d", row), strings.Join(tactics, ", "))
+			file.SetCellValue(sheetName, fmt.Sprintf("E%d", row), strings.Join(technique.Platforms, ", "))
+			file.SetCellValue(sheetName, fmt.Sprintf("F%d", row), rule.Location())
+			row++
+		}
+	}
+
+	return nil
+}
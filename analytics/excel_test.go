@@ -5,13 +5,16 @@ import (
 	"testing"
 
 	"github.com/mtnmunuklu/analyze-tags/analytics"
+	"github.com/mtnmunuklu/analyze-tags/attack"
+	"github.com/mtnmunuklu/analyze-tags/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
 )
 
 func TestToExcel(t *testing.T) {
-	data := map[string][]string{
-		"Rule1": {"tag1", "tag3", "tag2"},
-		"Rule2": {"tag1", "tag2"},
+	data := []model.Rule{
+		{Title: "Rule1", Tags: []string{"tag1", "tag3", "tag2"}},
+		{Title: "Rule2", Tags: []string{"tag1", "tag2"}},
 	}
 
 	params := analytics.ExcelParams{
@@ -30,3 +33,106 @@ func TestToExcel(t *testing.T) {
 
 	assert.Nil(t, err, "Expected error to be nil")
 }
+
+func TestToExcelGroupBy(t *testing.T) {
+	params := analytics.ExcelParams{
+		SheetName: "TestSheet",
+		Data: []model.Rule{
+			{Title: "Rule1", Tags: []string{"tag1", "tag3", "tag2"}, Severity: "high"},
+			{Title: "Rule2", Tags: []string{"tag1", "tag2"}},
+		},
+		Output:  "./data/output/test/output_severity.xlsx",
+		GroupBy: "severity",
+	}
+
+	err := params.ToExcel()
+	defer os.Remove(params.Output)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	file, err := excelize.OpenFile(params.Output)
+	if err != nil {
+		t.Fatalf("error opening workbook: %v", err)
+	}
+	defer file.Close()
+
+	rows, err := file.GetRows("By severity")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"high", "tag1", "1"}, rows[1])
+		assert.Len(t, rows, 6)
+	}
+}
+
+func TestToExcelGroupSheetName(t *testing.T) {
+	params := analytics.ExcelParams{
+		SheetName: "TestSheet",
+		Data:      []model.Rule{{Title: "Rule1", Tags: []string{"tag1"}}},
+		Output:    "./data/output/test/output_group.xlsx",
+		GroupBy:   "logsource/product:category [windows]",
+	}
+
+	err := params.ToExcel()
+	defer os.Remove(params.Output)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	file, err := excelize.OpenFile(params.Output)
+	if err != nil {
+		t.Fatalf("error opening workbook: %v", err)
+	}
+	defer file.Close()
+
+	assert.Contains(t, file.GetSheetList(), "By logsource_product_category _")
+}
+
+func TestGroupRules(t *testing.T) {
+	data := []model.Rule{
+		{Title: "Rule1", Severity: "high", Metadata: map[string][]string{"module": {"pe", "dotnet"}}},
+		{Title: "Rule2", Severity: "high", Metadata: map[string][]string{"module": {"pe"}}},
+		{Title: "Rule3"},
+	}
+
+	bySeverity := analytics.GroupRules(data, "severity")
+	assert.Len(t, bySeverity["high"], 2)
+	assert.Len(t, bySeverity[analytics.UngroupedValue], 1)
+
+	byModule := analytics.GroupRules(data, "module")
+	assert.Len(t, byModule["pe"], 2)
+	assert.Len(t, byModule["dotnet"], 1)
+	assert.Equal(t, "Rule3", byModule[analytics.UngroupedValue][0].Title)
+}
+
+func TestToExcelAttack(t *testing.T) {
+	matrix, err := attack.Load("../attack/data/enterprise-attack.json")
+	if err != nil {
+		t.Fatalf("error loading bundle: %v", err)
+	}
+
+	params := analytics.ExcelParams{
+		SheetName: "Data",
+		Data:      []model.Rule{{Title: "Rule1", Path: "rule1.yml", Tags: []string{"attack.execution", "attack.t1059.001"}}},
+		Output:    "./data/output/test/attack.xlsx",
+		Attack:    matrix,
+	}
+
+	err = params.ToExcel()
+	defer os.Remove(params.Output)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	file, err := excelize.OpenFile(params.Output)
+	if err != nil {
+		t.Fatalf("error opening workbook: %v", err)
+	}
+	defer file.Close()
+
+	rows, err := file.GetRows("ATT&CK")
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{
+		{"Rule", "Technique ID", "Technique", "Tactics", "Platforms", "Location"},
+		{"Rule1", "T1059.001", "Command and Scripting Interpreter: PowerShell", "Execution", "Windows", "rule1.yml#0"},
+	}, rows)
+}
@@ -0,0 +1,245 @@
+package analytics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+	"github.com/mtnmunuklu/analyze-tags/attack"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/xuri/excelize/v2"
+)
+
+type CoverageStatus string
+
+const (
+	Uncovered     CoverageStatus = "uncovered"
+	WeaklyCovered CoverageStatus = "weakly covered"
+	Covered       CoverageStatus = "covered"
+)
+
+// CoverageStatuses lists the statuses from the least to the most covered.
+var CoverageStatuses = []CoverageStatus{Uncovered, WeaklyCovered, Covered}
+
+var coverageSheetNames = map[CoverageStatus]string{
+	Uncovered:     "Uncovered",
+	WeaklyCovered: "Weakly Covered",
+	Covered:       "Covered",
+}
+
+// DefaultGapMinRules is the number of rules a technique needs to count as
+// covered rather than weakly covered.
+const DefaultGapMinRules = 2
+
+// TechniqueCoverage is the coverage of a single technique of a profile.
+// Rules lists the rules tagged with the technique or, for techniques, with
+// one of its sub-techniques.
+type TechniqueCoverage struct {
+	ID      string
+	Name    string
+	Tactics []string
+	Rules   []string
+	Status  CoverageStatus
+}
+
+type GapParams struct {
+	Profile attack.Profile
+	Data    []model.Rule
+
+	// MinRules is the number of rules below which a technique is weakly
+	// covered. DefaultGapMinRules is used when it is zero.
+	MinRules int
+
+	// Attack resolves ATT&CK tags given by name and adds the names and
+	// tactics of the techniques. Without it only tags holding technique ids
+	// are used.
+	Attack *attack.Matrix
+}
+
+// Coverage returns the coverage of every technique of the profile in the
+// order of the profile.
+func (g *GapParams) Coverage() []TechniqueCoverage {
+	minRules := g.MinRules
+	if minRules <= 0 {
+		minRules = DefaultGapMinRules
+	}
+
+	labels := ruleLabels(g.Data)
+	techniqueRules := make(map[string][]string)
+	for i, rule := range g.Data {
+		seen := make(map[string]bool)
+		for _, id := range techniqueIDs(g.Attack, rule.Tags) {
+			parent, _, _ := strings.Cut(id, ".")
+			for _, key := range []string{id, parent} {
+				if !seen[key] {
+					techniqueRules[key] = append(techniqueRules[key], labels[i])
+					seen[key] = true
+				}
+			}
+		}
+	}
+
+	var coverage []TechniqueCoverage
+	for _, id := range g.Profile.Techniques {
+		technique := TechniqueCoverage{ID: id, Rules: techniqueRules[id]}
+
+		switch {
+		case len(technique.Rules) == 0:
+			technique.Status = Uncovered
+		case len(technique.Rules) < minRules:
+			technique.Status = WeaklyCovered
+		default:
+			technique.Status = Covered
+		}
+
+		if g.Attack != nil {
+			technique.Name = g.Attack.FullName(id)
+			if found, ok := g.Attack.Technique(id); ok {
+				for _, shortName := range found.Tactics {
+					if tactic, ok := g.Attack.Tactic(shortName); ok {
+						technique.Tactics = append(technique.Tactics, tactic.Name)
+					}
+				}
+			}
+		}
+
+		coverage = append(coverage, technique)
+	}
+
+	return coverage
+}
+
+// ToExcel writes a summary sheet and one sheet per coverage status listing
+// the techniques and the rules covering them.
+func (g *GapParams) ToExcel(output string) error {
+	coverage := g.Coverage()
+
+	file := excelize.NewFile()
+	sheetName := "Gap Summary"
+	index, err := file.NewSheet(sheetName)
+	if err != nil {
+		return err
+	}
+
+	file.SetCellValue(sheetName, "A1", "Profile")
+	file.SetCellValue(sheetName, "B1", g.Profile.Name)
+	file.SetCellValue(sheetName, "A2", "Status")
+	file.SetCellValue(sheetName, "B2", "Techniques")
+
+	for i, status := range CoverageStatuses {
+		count := 0
+		for _, technique := range coverage {
+			if technique.Status == status {
+				count++
+			}
+		}
+		file.SetCellValue(sheetName, fmt.Sprintf("A%d", i+3), string(status))
+		file.SetCellValue(sheetName, fmt.Sprintf("B%d", i+3), count)
+	}
+
+	for _, status := range CoverageStatuses {
+		if err := writeCoverageSheet(file, status, coverage); err != nil {
+			return err
+		}
+	}
+
+	file.SetActiveSheet(index)
+
+	return file.SaveAs(output)
+}
+
+func writeCoverageSheet(file *excelize.File, status CoverageStatus, coverage []TechniqueCoverage) error {
+	sheetName := coverageSheetNames[status]
+	if _, err := file.NewSheet(sheetName); err != nil {
+		return err
+	}
+
+	file.SetCellValue(sheetName, "A1", "Technique ID")
+	file.SetCellValue(sheetName, "B1", "Technique")
+	file.SetCellValue(sheetName, "C1", "Tactics")
+	file.SetCellValue(sheetName, "D1", "Rule Count")
+	file.SetCellValue(sheetName, "E1", "Rules")
+
+	row := 2
+	for _, technique := range coverage {
+		if technique.Status != status {
+			continue
+		}
+		file.SetCellValue(sheetName, fmt.Sprintf("A%d", row), technique.ID)
+		file.SetCellValue(sheetName, fmt.Sprintf("B%d", row), technique.Name)
+		file.SetCellValue(sheetName, fmt.Sprintf("C%d", row), strings.Join(technique.Tactics, ", "))
+		file.SetCellValue(sheetName, fmt.Sprintf("D%d", row), len(technique.Rules))
+		file.SetCellValue(sheetName, fmt.Sprintf("E%d", row), strings.Join(technique.Rules, "\n"))
+		row++
+	}
+
+	return nil
+}
+
+// ToChart writes a bar chart stacking the uncovered, weakly covered and
+// covered techniques of the profile per tactic. Without a matrix the
+// techniques are counted in a single bar.
+func (g *GapParams) ToChart(output string) error {
+	coverage := g.Coverage()
+
+	var categories []string
+	if g.Attack != nil {
+		for _, tactic := range g.Attack.Tactics() {
+			categories = append(categories, tactic.Name)
+		}
+	} else {
+		categories = []string{"Techniques"}
+	}
+
+	counts := make(map[CoverageStatus]map[string]int)
+	used := make(map[string]bool)
+	for _, technique := range coverage {
+		tactics := technique.Tactics
+		if g.Attack == nil {
+			tactics = categories
+		}
+		for _, tactic := range tactics {
+			if counts[technique.Status] == nil {
+				counts[technique.Status] = make(map[string]int)
+			}
+			counts[technique.Status][tactic]++
+			used[tactic] = true
+		}
+	}
+
+	var xAxisData []string
+	for _, category := range categories {
+		if used[category] {
+			xAxisData = append(xAxisData, category)
+		}
+	}
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithTitleOpts(opts.Title{
+			Title:    fmt.Sprintf("Coverage gaps: %s", g.Profile.Name),
+			Subtitle: fmt.Sprintf("%d techniques", len(coverage)),
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    true,
+			Trigger: "axis",
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			AxisLabel: &opts.AxisLabel{Show: true, Interval: "0", Rotate: 30},
+		}),
+		charts.WithColorsOpts(opts.Colors{"#d94e5d", "#eac736", "#50a3ba"}),
+	)
+
+	bar.SetXAxis(xAxisData)
+	for _, status := range CoverageStatuses {
+		var seriesData []opts.BarData
+		for _, tactic := range xAxisData {
+			seriesData = append(seriesData, opts.BarData{Value: counts[status][tactic]})
+		}
+		bar.AddSeries(string(status), seriesData,
+			charts.WithBarChartOpts(opts.BarChart{Stack: "techniques"}))
+	}
+
+	return renderChartToFile(bar, output)
+}
@@ -0,0 +1,100 @@
+package analytics_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/analytics"
+	"github.com/mtnmunuklu/analyze-tags/attack"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestGapCoverage(t *testing.T) {
+	params := analytics.GapParams{
+		Profile: attack.Profile{Name: "test", Techniques: []string{"T1003.001", "T1059", "T1059.003", "T1110"}},
+		Data: []model.Rule{
+			{Title: "Rule1", Tags: []string{"attack.t1059.001", "attack.t1059.003"}},
+			{Title: "Rule2", Tags: []string{"attack.t1059"}},
+			{Title: "Rule3", Tags: []string{"mitre:T1110"}},
+		},
+	}
+
+	coverage := params.Coverage()
+	if !assert.Len(t, coverage, 4) {
+		return
+	}
+
+	assert.Equal(t, analytics.TechniqueCoverage{ID: "T1003.001", Status: analytics.Uncovered}, coverage[0])
+	assert.Equal(t, []string{"Rule1", "Rule2"}, coverage[1].Rules)
+	assert.Equal(t, analytics.Covered, coverage[1].Status)
+	assert.Equal(t, analytics.WeaklyCovered, coverage[2].Status)
+	assert.Equal(t, analytics.WeaklyCovered, coverage[3].Status)
+
+	params.MinRules = 1
+	assert.Equal(t, analytics.Covered, params.Coverage()[3].Status)
+}
+
+func TestGapOutputs(t *testing.T) {
+	matrix, err := attack.Load("../attack/data/enterprise-attack.json")
+	if err != nil {
+		t.Fatalf("error loading bundle: %v", err)
+	}
+
+	profile, err := attack.LoadProfile("S0002", matrix)
+	if err != nil {
+		t.Fatalf("error loading profile: %v", err)
+	}
+
+	params := analytics.GapParams{
+		Profile: profile,
+		Data: []model.Rule{
+			{Title: "Rule1", Tags: []string{"attack.t1003.001"}},
+			{Title: "Rule2", Tags: []string{"attack.credentials_from_password_stores"}},
+		},
+		MinRules: 1,
+		Attack:   matrix,
+	}
+
+	coverage := params.Coverage()
+	if assert.Len(t, coverage, 2) {
+		assert.Equal(t, "OS Credential Dumping: LSASS Memory", coverage[0].Name)
+		assert.Equal(t, []string{"Credential Access"}, coverage[0].Tactics)
+		assert.Equal(t, analytics.Covered, coverage[1].Status)
+	}
+
+	output := "./data/output/test/gap.xlsx"
+	err = params.ToExcel(output)
+	defer os.Remove(output)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	file, err := excelize.OpenFile(output)
+	if err != nil {
+		t.Fatalf("error opening output: %v", err)
+	}
+	defer file.Close()
+
+	value, _ := file.GetCellValue("Gap Summary", "B1")
+	assert.Equal(t, "Mimikatz (S0002)", value)
+	value, _ = file.GetCellValue("Gap Summary", "B5")
+	assert.Equal(t, "2", value)
+	value, _ = file.GetCellValue("Covered", "E2")
+	assert.Equal(t, "Rule1", value)
+
+	chart := "./data/output/test/gap_chart.html"
+	err = params.ToChart(chart)
+	defer os.Remove(chart)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	contents, err := os.ReadFile(chart)
+	if err != nil {
+		t.Fatalf("Chart file was not created: %v", err)
+	}
+	assert.Contains(t, string(contents), `"data":["Credential Access"]`)
+	assert.Contains(t, string(contents), `"stack":"techniques"`)
+}
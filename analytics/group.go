@@ -0,0 +1,42 @@
+package analytics
+
+import (
+	"sort"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+// UngroupedValue is the group of rules that have no value for the field
+// they are grouped by.
+const UngroupedValue = "unknown"
+
+// GroupRules splits rules by the values of a field, e.g. severity, author,
+// format or a metadata key such as product or module. A rule with several
+// values for the field is added to every matching group.
+func GroupRules(rules []model.Rule, field string) map[string][]model.Rule {
+	groups := make(map[string][]model.Rule)
+	for _, rule := range rules {
+		values := rule.Field(field)
+		if len(values) == 0 {
+			values = []string{UngroupedValue}
+		}
+
+		seen := make(map[string]bool)
+		for _, value := range values {
+			if !seen[value] {
+				groups[value] = append(groups[value], rule)
+				seen[value] = true
+			}
+		}
+	}
+	return groups
+}
+
+func sortedGroupNames(groups map[string][]model.Rule) []string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,155 @@
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/attack"
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+// DefaultNavigatorColors is the gradient of the layer from techniques with
+// no rules to the technique with the most rules.
+var DefaultNavigatorColors = []string{"#ffffff", "#66b1ff"}
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}(?:[0-9a-fA-F]{2})?$`)
+
+type NavigatorParams struct {
+	Name   string
+	Data   []model.Rule
+	Output string
+	Colors []string
+
+	// Attack resolves ATT&CK tags given by name and sets the ATT&CK version
+	// of the layer. Without it only tags holding technique ids are used.
+	Attack *attack.Matrix
+}
+
+// ToNavigator writes an ATT&CK Navigator layer scoring every technique by
+// the number of rules tagged with it. The comment of each technique lists
+// the rules.
+func (n *NavigatorParams) ToNavigator() error {
+	layer, err := n.Layer()
+	if err != nil {
+		return err
+	}
+
+	output, err := json.MarshalIndent(layer, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(n.Output, output, 0644)
+}
+
+// Layer returns the layer written by ToNavigator.
+func (n *NavigatorParams) Layer() (attack.Layer, error) {
+	colors := n.Colors
+	if len(colors) == 0 {
+		colors = DefaultNavigatorColors
+	}
+	if len(colors) < 2 {
+		return attack.Layer{}, fmt.Errorf("the gradient needs at least two colors")
+	}
+	for _, color := range colors {
+		if !colorPattern.MatchString(color) {
+			return attack.Layer{}, fmt.Errorf("invalid gradient color: %s", color)
+		}
+	}
+
+	labels := ruleLabels(n.Data)
+	techniqueRules := make(map[string][]string)
+	for i, rule := range n.Data {
+		for _, id := range techniqueIDs(n.Attack, rule.Tags) {
+			techniqueRules[id] = append(techniqueRules[id], labels[i])
+		}
+	}
+
+	// Parents of tagged sub-techniques are listed without a score when no
+	// rule is tagged with them, so the layer shows their sub-techniques.
+	ids := make([]string, 0, len(techniqueRules))
+	parents := make(map[string]bool)
+	for id := range techniqueRules {
+		ids = append(ids, id)
+		if parent, _, ok := strings.Cut(id, "."); ok {
+			parents[parent] = true
+		}
+	}
+	for parent := range parents {
+		if _, ok := techniqueRules[parent]; !ok {
+			ids = append(ids, parent)
+		}
+	}
+	sort.Strings(ids)
+
+	maxScore := 1
+	var techniques []attack.LayerTechnique
+	for _, id := range ids {
+		technique := attack.LayerTechnique{
+			TechniqueID:       id,
+			ShowSubtechniques: parents[id],
+		}
+
+		if rules := techniqueRules[id]; len(rules) > 0 {
+			if len(rules) > maxScore {
+				maxScore = len(rules)
+			}
+
+			score := float64(len(rules))
+			technique.Score = &score
+			technique.Comment = strings.Join(rules, "\n")
+		}
+
+		techniques = append(techniques, technique)
+	}
+
+	name := n.Name
+	if name == "" {
+		name = "analyze-tags coverage"
+	}
+
+	layer := attack.Layer{
+		Name:        name,
+		Domain:      "enterprise-attack",
+		Description: fmt.Sprintf("%d rules tagged with %d techniques", len(n.Data), len(techniqueRules)),
+		Versions: attack.LayerVersions{
+			Navigator: attack.NavigatorVersion,
+			Layer:     attack.LayerVersion,
+		},
+		Techniques: techniques,
+		Gradient: &attack.Gradient{
+			Colors:   colors,
+			MinValue: 0,
+			MaxValue: float64(maxScore),
+		},
+		LegendItems: []attack.LegendItem{},
+	}
+	if n.Attack != nil {
+		layer.Versions.Attack = n.Attack.Version()
+	}
+
+	return layer, nil
+}
+
+// techniqueIDs returns the techniques a tag list refers to, resolving tags
+// given by name when a matrix is loaded.
+func techniqueIDs(matrix *attack.Matrix, tags []string) []string {
+	if matrix != nil {
+		return matrix.TechniqueIDs(tags)
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		ref, ok := attack.ParseID(tag)
+		if ok && ref.Kind != attack.TacticRef && !seen[ref.ID] {
+			ids = append(ids, ref.ID)
+			seen[ref.ID] = true
+		}
+	}
+	return ids
+}
@@ -0,0 +1,82 @@
+package analytics_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/analytics"
+	"github.com/mtnmunuklu/analyze-tags/attack"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToNavigator(t *testing.T) {
+	params := analytics.NavigatorParams{
+		Data: []model.Rule{
+			{Title: "Rule1", Tags: []string{"attack.execution", "attack.t1059.001"}},
+			{Title: "Rule2", Tags: []string{"mitre:T1059.001", "T1110"}},
+			{Title: "Rule3", Tags: []string{"container"}},
+		},
+		Output: "./data/output/test/layer.json",
+		Colors: []string{"#ffffff", "#ff6666"},
+	}
+
+	err := params.ToNavigator()
+	defer os.Remove(params.Output)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	layer, err := attack.LoadLayer(params.Output)
+	if err != nil {
+		t.Fatalf("error reading layer: %v", err)
+	}
+
+	assert.Equal(t, "enterprise-attack", layer.Domain)
+	assert.Equal(t, attack.LayerVersion, layer.Versions.Layer)
+	assert.Equal(t, []string{"#ffffff", "#ff6666"}, layer.Gradient.Colors)
+	assert.Equal(t, 2.0, layer.Gradient.MaxValue)
+
+	if assert.Len(t, layer.Techniques, 3) {
+		assert.Equal(t, "T1059", layer.Techniques[0].TechniqueID)
+		assert.Nil(t, layer.Techniques[0].Score)
+		assert.True(t, layer.Techniques[0].ShowSubtechniques)
+		assert.Equal(t, "T1059.001", layer.Techniques[1].TechniqueID)
+		assert.Equal(t, 2.0, *layer.Techniques[1].Score)
+		assert.Equal(t, "Rule1\nRule2", layer.Techniques[1].Comment)
+		assert.Equal(t, "T1110", layer.Techniques[2].TechniqueID)
+		assert.Equal(t, "Rule2", layer.Techniques[2].Comment)
+	}
+}
+
+func TestNavigatorLayerAttack(t *testing.T) {
+	matrix, err := attack.Load("../attack/data/enterprise-attack.json")
+	if err != nil {
+		t.Fatalf("error loading bundle: %v", err)
+	}
+
+	params := analytics.NavigatorParams{
+		Data: []model.Rule{
+			{Title: "Rule1", Tags: []string{"mitre_technique_name:Command_And_Scripting_Interpreter", "attack.t1059.001"}},
+		},
+		Attack: matrix,
+	}
+
+	layer, err := params.Layer()
+	if err != nil {
+		t.Fatalf("error building layer: %v", err)
+	}
+
+	assert.Equal(t, "14", layer.Versions.Attack)
+	assert.Equal(t, analytics.DefaultNavigatorColors, layer.Gradient.Colors)
+	if assert.Len(t, layer.Techniques, 2) {
+		assert.Equal(t, "T1059", layer.Techniques[0].TechniqueID)
+		assert.Equal(t, 1.0, *layer.Techniques[0].Score)
+		assert.True(t, layer.Techniques[0].ShowSubtechniques)
+		assert.False(t, layer.Techniques[1].ShowSubtechniques)
+	}
+
+	params.Colors = []string{"blue", "#ffffff"}
+	_, err = params.Layer()
+	assert.EqualError(t, err, "invalid gradient color: blue")
+}
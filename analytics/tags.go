@@ -0,0 +1,46 @@
+package analytics
+
+import (
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+// FilterTags keeps only the tags in the given namespaces, e.g. cwe keeps
+// cwe:CWE-79, so the charts show the distribution of a single kind of tag.
+// Rules left without tags are dropped.
+func FilterTags(rules []model.Rule, namespaces []string) []model.Rule {
+	var filtered []model.Rule
+	for _, rule := range rules {
+		var tags []string
+		for _, tag := range rule.Tags {
+			for _, namespace := range namespaces {
+				if strings.HasPrefix(tag, namespace+":") {
+					tags = append(tags, tag)
+					break
+				}
+			}
+		}
+
+		if len(tags) > 0 {
+			rule.Tags = tags
+			filtered = append(filtered, rule)
+		}
+	}
+	return filtered
+}
+
+// FieldTags replaces the tags of every rule with the values of a field, so
+// the charts show the distribution of the field instead, e.g. of the
+// attack_tactic or attack_platform fields added by ATT&CK enrichment. Rules
+// without a value for the field are dropped.
+func FieldTags(rules []model.Rule, field string) []model.Rule {
+	var fielded []model.Rule
+	for _, rule := range rules {
+		if values := rule.Field(field); len(values) > 0 {
+			rule.Tags = values
+			fielded = append(fielded, rule)
+		}
+	}
+	return fielded
+}
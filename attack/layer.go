@@ -0,0 +1,72 @@
+package attack
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// Layer is an ATT&CK Navigator layer in the v4.x file format. Only the
+// fields written by the navigator export and read by the gap analysis are
+// modelled.
+type Layer struct {
+	Name         string           `json:"name"`
+	Versions     LayerVersions    `json:"versions"`
+	Domain       string           `json:"domain"`
+	Description  string           `json:"description"`
+	Techniques   []LayerTechnique `json:"techniques"`
+	Gradient     *Gradient        `json:"gradient,omitempty"`
+	LegendItems  []LegendItem     `json:"legendItems"`
+	HideDisabled bool             `json:"hideDisabled"`
+}
+
+type LayerVersions struct {
+	Attack    string `json:"attack,omitempty"`
+	Navigator string `json:"navigator"`
+	Layer     string `json:"layer"`
+}
+
+type LayerTechnique struct {
+	TechniqueID       string          `json:"techniqueID"`
+	Tactic            string          `json:"tactic,omitempty"`
+	Score             *float64        `json:"score,omitempty"`
+	Color             string          `json:"color,omitempty"`
+	Comment           string          `json:"comment,omitempty"`
+	Enabled           *bool           `json:"enabled,omitempty"`
+	Metadata          []LayerMetadata `json:"metadata,omitempty"`
+	ShowSubtechniques bool            `json:"showSubtechniques"`
+}
+
+type LayerMetadata struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type Gradient struct {
+	Colors   []string `json:"colors"`
+	MinValue float64  `json:"minValue"`
+	MaxValue float64  `json:"maxValue"`
+}
+
+type LegendItem struct {
+	Label string `json:"label"`
+	Color string `json:"color"`
+}
+
+// Layer format versions written by the navigator export.
+const (
+	LayerVersion     = "4.5"
+	NavigatorVersion = "4.9.1"
+)
+
+// LoadLayer reads a Navigator layer from a local file.
+func LoadLayer(path string) (Layer, error) {
+	layer := Layer{}
+
+	input, err := os.ReadFile(path)
+	if err != nil {
+		return layer, err
+	}
+
+	err = json.Unmarshal(input, &layer)
+	return layer, err
+}
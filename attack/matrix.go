@@ -0,0 +1,288 @@
+package attack
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/stix"
+)
+
+const mitreAttack = "mitre-attack"
+
+type Tactic struct {
+	ID        string
+	ShortName string
+	Name      string
+}
+
+// Technique is an ATT&CK technique or sub-technique. Tactics holds the short
+// names of the tactics the technique belongs to, e.g. credential-access.
+type Technique struct {
+	ID         string
+	Name       string
+	Tactics    []string
+	Platforms  []string
+	Deprecated bool
+	Revoked    bool
+	RevokedBy  string
+}
+
+// IsSubtechnique reports whether the technique is a sub-technique such as
+// T1059.001.
+func (t Technique) IsSubtechnique() bool {
+	return strings.Contains(t.ID, ".")
+}
+
+// Parent returns the id of the technique a sub-technique belongs to, or the
+// id of the technique itself.
+func (t Technique) Parent() string {
+	parent, _, _ := strings.Cut(t.ID, ".")
+	return parent
+}
+
+// Entity is an ATT&CK group or software with the ids of the techniques and
+// sub-techniques it is known to use. Type is the STIX type of the object:
+// intrusion-set, malware or tool.
+type Entity struct {
+	ID         string
+	Name       string
+	Type       string
+	Techniques []string
+}
+
+// Matrix is the ATT&CK knowledge base loaded from a STIX bundle such as
+// enterprise-attack.json of the mitre/cti repository.
+type Matrix struct {
+	version        string
+	tactics        []Tactic
+	techniques     map[string]Technique
+	techniqueNames map[string]string
+	entities       map[string]Entity
+}
+
+type object struct {
+	Type               string                   `json:"type"`
+	ID                 string                   `json:"id"`
+	Name               string                   `json:"name"`
+	ExternalReferences []stix.ExternalReference `json:"external_references"`
+	KillChainPhases    []stix.KillChainPhase    `json:"kill_chain_phases"`
+	ShortName          string                   `json:"x_mitre_shortname"`
+	Platforms          []string                 `json:"x_mitre_platforms"`
+	Deprecated         bool                     `json:"x_mitre_deprecated"`
+	Revoked            bool                     `json:"revoked"`
+	TacticRefs         []string                 `json:"tactic_refs"`
+	Version            string                   `json:"x_mitre_version"`
+	RelationshipType   string                   `json:"relationship_type"`
+	SourceRef          string                   `json:"source_ref"`
+	TargetRef          string                   `json:"target_ref"`
+}
+
+func (o object) externalID() string {
+	for _, reference := range o.ExternalReferences {
+		if reference.SourceName == mitreAttack {
+			return reference.ExternalID
+		}
+	}
+	return ""
+}
+
+// Load reads an ATT&CK STIX bundle from a local file.
+func Load(path string) (*Matrix, error) {
+	input, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(input)
+}
+
+// Parse reads an ATT&CK STIX bundle. Tactics are ordered as in the matrix
+// object of the bundle, or in the order they appear when it has none.
+func Parse(input []byte) (*Matrix, error) {
+	bundle := struct {
+		Objects []object `json:"objects"`
+	}{}
+	if err := json.Unmarshal(input, &bundle); err != nil {
+		return nil, err
+	}
+
+	m := &Matrix{
+		techniques:     make(map[string]Technique),
+		techniqueNames: make(map[string]string),
+		entities:       make(map[string]Entity),
+	}
+
+	tactics := make(map[string]Tactic)
+	var tacticOrder []string
+	techniqueIDs := make(map[string]string)
+	entityIDs := make(map[string]string)
+
+	for _, o := range bundle.Objects {
+		switch o.Type {
+		case "x-mitre-tactic":
+			tactics[o.ID] = Tactic{ID: o.externalID(), ShortName: o.ShortName, Name: o.Name}
+			tacticOrder = append(tacticOrder, o.ID)
+		case "x-mitre-collection":
+			m.version = o.Version
+		case "x-mitre-matrix":
+			if len(o.TacticRefs) > 0 {
+				tacticOrder = o.TacticRefs
+			}
+		case "attack-pattern":
+			id := o.externalID()
+			if id == "" {
+				continue
+			}
+
+			technique := Technique{
+				ID:         id,
+				Name:       o.Name,
+				Platforms:  o.Platforms,
+				Deprecated: o.Deprecated,
+				Revoked:    o.Revoked,
+			}
+			for _, phase := range o.KillChainPhases {
+				if phase.KillChainName == mitreAttack {
+					technique.Tactics = append(technique.Tactics, phase.PhaseName)
+				}
+			}
+
+			m.techniques[id] = technique
+			techniqueIDs[o.ID] = id
+		case "intrusion-set", "malware", "tool":
+			id := o.externalID()
+			if id == "" || o.Revoked || o.Deprecated {
+				continue
+			}
+
+			m.entities[id] = Entity{ID: id, Name: o.Name, Type: o.Type}
+			entityIDs[o.ID] = id
+		}
+	}
+
+	for _, o := range bundle.Objects {
+		if o.Type != "relationship" || o.Revoked || o.Deprecated {
+			continue
+		}
+
+		switch o.RelationshipType {
+		case "revoked-by":
+			id, replacement := techniqueIDs[o.SourceRef], techniqueIDs[o.TargetRef]
+			if technique, ok := m.techniques[id]; ok && replacement != "" {
+				technique.RevokedBy = replacement
+				m.techniques[id] = technique
+			}
+		case "uses":
+			id, techniqueID := entityIDs[o.SourceRef], techniqueIDs[o.TargetRef]
+			if entity, ok := m.entities[id]; ok && techniqueID != "" {
+				entity.Techniques = append(entity.Techniques, techniqueID)
+				m.entities[id] = entity
+			}
+		}
+	}
+
+	for id, entity := range m.entities {
+		sort.Strings(entity.Techniques)
+		m.entities[id] = entity
+	}
+
+	for _, ref := range tacticOrder {
+		if tactic, ok := tactics[ref]; ok {
+			m.tactics = append(m.tactics, tactic)
+		}
+	}
+
+	m.indexNames()
+
+	return m, nil
+}
+
+// indexNames maps the normalized names of the current techniques to their ids.
+// Sub-techniques are indexed by their own name and by "technique: name", and
+// names shared by several techniques are left out.
+func (m *Matrix) indexNames() {
+	counts := make(map[string]int)
+	names := make(map[string]string)
+	add := func(name, id string) {
+		name = normalizeName(name)
+		counts[name]++
+		names[name] = id
+	}
+
+	for id, technique := range m.techniques {
+		if technique.Revoked || technique.Deprecated {
+			continue
+		}
+		add(technique.Name, id)
+		if technique.IsSubtechnique() {
+			add(m.FullName(id), id)
+		}
+	}
+
+	for name, id := range names {
+		if counts[name] == 1 {
+			m.techniqueNames[name] = id
+		}
+	}
+}
+
+// Version returns the major ATT&CK version of the bundle, e.g. 14 for
+// release 14.1, or an empty string when the bundle has no collection object.
+func (m *Matrix) Version() string {
+	major, _, _ := strings.Cut(m.version, ".")
+	return major
+}
+
+// Tactics returns the tactics in matrix order.
+func (m *Matrix) Tactics() []Tactic {
+	return m.tactics
+}
+
+// Tactic returns a tactic by id, short name or name.
+func (m *Matrix) Tactic(key string) (Tactic, bool) {
+	for _, tactic := range m.tactics {
+		if strings.EqualFold(tactic.ID, key) || strings.EqualFold(tactic.ShortName, key) || strings.EqualFold(tactic.Name, key) {
+			return tactic, true
+		}
+	}
+	return Tactic{}, false
+}
+
+func (m *Matrix) Technique(id string) (Technique, bool) {
+	technique, ok := m.techniques[strings.ToUpper(id)]
+	return technique, ok
+}
+
+// Techniques returns every technique and sub-technique sorted by id,
+// including deprecated and revoked ones.
+func (m *Matrix) Techniques() []Technique {
+	techniques := make([]Technique, 0, len(m.techniques))
+	for _, technique := range m.techniques {
+		techniques = append(techniques, technique)
+	}
+	sort.Slice(techniques, func(i, j int) bool {
+		return techniques[i].ID < techniques[j].ID
+	})
+	return techniques
+}
+
+// FullName returns the name of a technique, prefixed with the name of its
+// parent for sub-techniques, e.g. "Command and Scripting Interpreter:
+// PowerShell".
+func (m *Matrix) FullName(id string) string {
+	technique, ok := m.Technique(id)
+	if !ok {
+		return ""
+	}
+	if parent, ok := m.Technique(technique.Parent()); ok && technique.IsSubtechnique() {
+		return parent.Name + ": " + technique.Name
+	}
+	return technique.Name
+}
+
+// Entity returns a group or software by id, e.g. G0049 or S0002.
+func (m *Matrix) Entity(id string) (Entity, bool) {
+	entity, ok := m.entities[strings.ToUpper(id)]
+	return entity, ok
+}
@@ -0,0 +1,154 @@
+package attack_test
+
+import (
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/attack"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadMatrix(t *testing.T) *attack.Matrix {
+	matrix, err := attack.Load("./data/enterprise-attack.json")
+	if err != nil {
+		t.Fatalf("error loading bundle: %v", err)
+	}
+	return matrix
+}
+
+func TestLoad(t *testing.T) {
+	matrix := loadMatrix(t)
+	assert.Equal(t, "14", matrix.Version())
+
+	tactics := matrix.Tactics()
+	if assert.Len(t, tactics, 14) {
+		assert.Equal(t, attack.Tactic{ID: "TA0043", ShortName: "reconnaissance", Name: "Reconnaissance"}, tactics[0])
+		assert.Equal(t, "TA0040", tactics[13].ID)
+	}
+
+	technique, ok := matrix.Technique("t1053.005")
+	if assert.True(t, ok) {
+		assert.Equal(t, "Scheduled Task", technique.Name)
+		assert.Equal(t, []string{"execution", "persistence", "privilege-escalation"}, technique.Tactics)
+		assert.Equal(t, []string{"Windows"}, technique.Platforms)
+		assert.True(t, technique.IsSubtechnique())
+		assert.Equal(t, "T1053", technique.Parent())
+	}
+	assert.Equal(t, "Scheduled Task/Job: Scheduled Task", matrix.FullName("T1053.005"))
+
+	technique, _ = matrix.Technique("T1043")
+	assert.True(t, technique.Deprecated)
+
+	technique, _ = matrix.Technique("T1064")
+	assert.True(t, technique.Revoked)
+	assert.Equal(t, "T1059", technique.RevokedBy)
+}
+
+func TestParseID(t *testing.T) {
+	tests := map[string]attack.Ref{
+		"attack.t1059.001":                    {Kind: attack.SubtechniqueRef, ID: "T1059.001"},
+		"T1059":                               {Kind: attack.TechniqueRef, ID: "T1059"},
+		"mitre:T1110.001":                     {Kind: attack.SubtechniqueRef, ID: "T1110.001"},
+		"mitre_attack_technique_id:T1036/005": {Kind: attack.SubtechniqueRef, ID: "T1036.005"},
+		"mitre_tactic_id:TA0011":              {Kind: attack.TacticRef, ID: "TA0011"},
+	}
+
+	for tag, expected := range tests {
+		ref, ok := attack.ParseID(tag)
+		assert.True(t, ok, tag)
+		assert.Equal(t, expected, ref, tag)
+	}
+
+	for _, tag := range []string{"attack.t105", "attack.g0049", "attack.execution", "TA0001.001", "cve:CVE-2021-44228"} {
+		_, ok := attack.ParseID(tag)
+		assert.False(t, ok, tag)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	matrix := loadMatrix(t)
+
+	tests := map[string]attack.Ref{
+		"attack.credential_access":                                               {Kind: attack.TacticRef, ID: "TA0006"},
+		"mitre_execution":                                                        {Kind: attack.TacticRef, ID: "TA0002"},
+		"mitre_tactic_name:Command_And_Control":                                  {Kind: attack.TacticRef, ID: "TA0011"},
+		"mitre_attack_tactic:Defense Evasion":                                    {Kind: attack.TacticRef, ID: "TA0005"},
+		"mitre_technique_name:Application_Layer_Protocol":                        {Kind: attack.TechniqueRef, ID: "T1071"},
+		"mitre_attack_technique:Masquerading: Match Legitimate Name or Location": {Kind: attack.SubtechniqueRef, ID: "T1036.005"},
+		"mitre_attack_technique:Exploit Public-Facing Application":               {Kind: attack.TechniqueRef, ID: "T1190"},
+	}
+
+	for tag, expected := range tests {
+		ref, ok := matrix.Resolve(tag)
+		assert.True(t, ok, tag)
+		assert.Equal(t, expected, ref, tag)
+	}
+
+	for _, tag := range []string{"execution", "container", "attack.g0049", "group:authentication_failed"} {
+		_, ok := matrix.Resolve(tag)
+		assert.False(t, ok, tag)
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	matrix := loadMatrix(t)
+
+	rule := model.Rule{
+		Title:    "Terminal shell in container",
+		Tags:     []string{"container", "mitre_execution", "T1059", "attack.t1059"},
+		Metadata: map[string][]string{"source": {"syscall"}},
+	}
+
+	enriched := matrix.Enrich(rule)
+	assert.Equal(t, []string{"container", "attack.execution", "attack.t1059"}, enriched.Tags)
+	assert.Equal(t, []string{"Command and Scripting Interpreter"}, enriched.Field("attack_technique"))
+	assert.Equal(t, []string{"Execution"}, enriched.Field("attack_tactic"))
+	assert.Equal(t, []string{"Linux", "macOS", "Windows", "Network"}, enriched.Field("attack_platform"))
+	assert.Equal(t, []string{"syscall"}, enriched.Field("source"))
+	assert.Nil(t, rule.Field("attack_tactic"))
+
+	enriched = matrix.Enrich(model.Rule{Tags: []string{"mitre:T1053.005"}})
+	assert.Equal(t, []string{"attack.t1053.005"}, enriched.Tags)
+	assert.Equal(t, []string{"Scheduled Task/Job: Scheduled Task"}, enriched.Field("attack_technique"))
+	assert.Equal(t, []string{"Execution", "Persistence", "Privilege Escalation"}, enriched.Field("attack_tactic"))
+}
+
+func TestLoadProfile(t *testing.T) {
+	matrix := loadMatrix(t)
+
+	entity, ok := matrix.Entity("s0002")
+	if assert.True(t, ok) {
+		assert.Equal(t, attack.Entity{ID: "S0002", Name: "Mimikatz", Type: "tool", Techniques: []string{"T1003.001", "T1555"}}, entity)
+	}
+
+	profile, err := attack.LoadProfile("G0049", matrix)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "OilRig (G0049)", profile.Name)
+		assert.Len(t, profile.Techniques, 10)
+		assert.Contains(t, profile.Techniques, "T1059.001")
+	}
+
+	_, err = attack.LoadProfile("G0049", nil)
+	assert.EqualError(t, err, "resolving G0049 needs an ATT&CK bundle")
+	_, err = attack.LoadProfile("G9999", matrix)
+	assert.EqualError(t, err, "unknown ATT&CK group or software: G9999")
+
+	profile, err = attack.LoadProfile("T1110, t1059.001,T1110", nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"T1059.001", "T1110"}, profile.Techniques)
+	}
+	_, err = attack.LoadProfile("T1110,TA0006", nil)
+	assert.EqualError(t, err, "not a technique id: TA0006")
+
+	zero, disabled := 0.0, false
+	profile = attack.LayerProfile(attack.Layer{
+		Name: "layer",
+		Techniques: []attack.LayerTechnique{
+			{TechniqueID: "T1566.001"},
+			{TechniqueID: "T1082", Score: &zero},
+			{TechniqueID: "T1005", Enabled: &disabled},
+			{TechniqueID: "T1021"},
+		},
+	})
+	assert.Equal(t, attack.Profile{Name: "layer", Techniques: []string{"T1021", "T1566.001"}}, profile)
+}
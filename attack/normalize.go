@@ -0,0 +1,222 @@
+package attack
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+type Kind string
+
+const (
+	TacticRef       Kind = "tactic"
+	TechniqueRef    Kind = "technique"
+	SubtechniqueRef Kind = "sub-technique"
+)
+
+// Ref is a reference to an ATT&CK tactic, technique or sub-technique by its
+// canonical id, e.g. TA0006, T1110 or T1110.001.
+type Ref struct {
+	Kind Kind
+	ID   string
+}
+
+var idPattern = regexp.MustCompile(`^(?i)(ta|t)(\d{4})(?:[./](\d{3}))?$`)
+
+// splitTag returns the namespace and value of a tag. The namespace is attack
+// for Sigma tags such as attack.t1059, the part before the first colon for
+// tags such as mitre:T1110 or mitre_attack_tactic:Defense Evasion, and mitre
+// for Falco tags such as mitre_execution.
+func splitTag(tag string) (string, string) {
+	lower := strings.ToLower(tag)
+	switch {
+	case strings.HasPrefix(lower, "attack."):
+		return "attack", tag[len("attack."):]
+	case strings.Contains(tag, ":"):
+		namespace, value, _ := strings.Cut(tag, ":")
+		return strings.ToLower(strings.TrimSpace(namespace)), strings.TrimSpace(value)
+	case strings.HasPrefix(lower, "mitre_"):
+		return "mitre", tag[len("mitre_"):]
+	default:
+		return "", tag
+	}
+}
+
+// ParseID returns the ATT&CK id a tag refers to when the tag value is an id,
+// whatever the format of the tag: attack.t1059.001, T1059.001,
+// mitre:T1059.001 and mitre_attack_technique_id:T1059/001 all refer to the
+// sub-technique T1059.001.
+func ParseID(tag string) (Ref, bool) {
+	_, value := splitTag(tag)
+	match := idPattern.FindStringSubmatch(value)
+	if match == nil {
+		return Ref{}, false
+	}
+
+	prefix := strings.ToUpper(match[1])
+	switch {
+	case prefix == "TA" && match[3] == "":
+		return Ref{Kind: TacticRef, ID: "TA" + match[2]}, true
+	case prefix == "T" && match[3] == "":
+		return Ref{Kind: TechniqueRef, ID: "T" + match[2]}, true
+	case prefix == "T":
+		return Ref{Kind: SubtechniqueRef, ID: "T" + match[2] + "." + match[3]}, true
+	default:
+		return Ref{}, false
+	}
+}
+
+// Resolve returns the ATT&CK object a tag refers to. Besides ids, tactics and
+// techniques are resolved by name in ATT&CK namespaces, e.g.
+// attack.credential_access, mitre_execution, mitre_tactic_name:Command_And_Control
+// or mitre_technique_name:Application_Layer_Protocol.
+func (m *Matrix) Resolve(tag string) (Ref, bool) {
+	if ref, ok := ParseID(tag); ok {
+		return ref, true
+	}
+
+	namespace, value := splitTag(tag)
+	if namespace != "attack" && !strings.Contains(namespace, "mitre") &&
+		!strings.Contains(namespace, "tactic") && !strings.Contains(namespace, "technique") {
+		return Ref{}, false
+	}
+
+	name := normalizeName(value)
+	if !strings.Contains(namespace, "technique") {
+		for _, tactic := range m.tactics {
+			if name == normalizeName(tactic.ShortName) || name == normalizeName(tactic.Name) {
+				return Ref{Kind: TacticRef, ID: tactic.ID}, true
+			}
+		}
+	}
+	if !strings.Contains(namespace, "tactic") {
+		if id, ok := m.techniqueNames[name]; ok {
+			technique := m.techniques[id]
+			if technique.IsSubtechnique() {
+				return Ref{Kind: SubtechniqueRef, ID: id}, true
+			}
+			return Ref{Kind: TechniqueRef, ID: id}, true
+		}
+	}
+
+	return Ref{}, false
+}
+
+func normalizeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.NewReplacer("_", " ", "-", " ").Replace(name)
+}
+
+// Refs returns the ATT&CK objects referred to by a tag list, without
+// duplicates.
+func (m *Matrix) Refs(tags []string) []Ref {
+	var refs []Ref
+	seen := make(map[Ref]bool)
+	for _, tag := range tags {
+		if ref, ok := m.Resolve(tag); ok && !seen[ref] {
+			refs = append(refs, ref)
+			seen[ref] = true
+		}
+	}
+	return refs
+}
+
+// TechniqueIDs returns the ids of the techniques and sub-techniques referred
+// to by a tag list.
+func (m *Matrix) TechniqueIDs(tags []string) []string {
+	var ids []string
+	for _, ref := range m.Refs(tags) {
+		if ref.Kind != TacticRef {
+			ids = append(ids, ref.ID)
+		}
+	}
+	return ids
+}
+
+// Tag returns the tag of a reference in the Sigma vocabulary, e.g.
+// attack.credential_access or attack.t1110.001.
+func (m *Matrix) Tag(ref Ref) string {
+	if ref.Kind == TacticRef {
+		if tactic, ok := m.Tactic(ref.ID); ok {
+			return "attack." + strings.ReplaceAll(tactic.ShortName, "-", "_")
+		}
+	}
+	return "attack." + strings.ToLower(ref.ID)
+}
+
+// NormalizeTags rewrites the tags that refer to ATT&CK objects into the Sigma
+// vocabulary and removes the duplicates this creates. Other tags are kept
+// as they are.
+func (m *Matrix) NormalizeTags(tags []string) []string {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		if ref, ok := m.Resolve(tag); ok {
+			tag = m.Tag(ref)
+		}
+		if !seen[tag] {
+			normalized = append(normalized, tag)
+			seen[tag] = true
+		}
+	}
+	return normalized
+}
+
+// Enrich normalizes the ATT&CK tags of a rule and adds the names of its
+// techniques, tactics and platforms as the attack_technique, attack_tactic
+// and attack_platform metadata fields. Rules tagged with techniques only get
+// the tactics of those techniques.
+func (m *Matrix) Enrich(rule model.Rule) model.Rule {
+	refs := m.Refs(rule.Tags)
+	rule.Tags = m.NormalizeTags(rule.Tags)
+
+	metadata := make(map[string][]string, len(rule.Metadata)+3)
+	for key, values := range rule.Metadata {
+		metadata[key] = values
+	}
+	rule.Metadata = metadata
+
+	var tactics, techniqueTactics []string
+	var techniques, platforms []string
+	for _, ref := range refs {
+		if ref.Kind == TacticRef {
+			if tactic, ok := m.Tactic(ref.ID); ok {
+				tactics = appendUnique(tactics, tactic.Name)
+			}
+			continue
+		}
+
+		technique, ok := m.Technique(ref.ID)
+		if !ok {
+			continue
+		}
+		techniques = appendUnique(techniques, m.FullName(ref.ID))
+		for _, platform := range technique.Platforms {
+			platforms = appendUnique(platforms, platform)
+		}
+		for _, shortName := range technique.Tactics {
+			if tactic, ok := m.Tactic(shortName); ok {
+				techniqueTactics = appendUnique(techniqueTactics, tactic.Name)
+			}
+		}
+	}
+	if len(tactics) == 0 {
+		tactics = techniqueTactics
+	}
+
+	rule.AddMetadata("attack_technique", techniques...)
+	rule.AddMetadata("attack_tactic", tactics...)
+	rule.AddMetadata("attack_platform", platforms...)
+
+	return rule
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,85 @@
+package attack
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Profile is a set of techniques and sub-techniques to measure the rule
+// coverage against, such as the techniques used by a threat group.
+type Profile struct {
+	Name       string
+	Techniques []string
+}
+
+var entityPattern = regexp.MustCompile(`^(?i)[GS]\d{4}$`)
+
+// LoadProfile returns the profile described by target, which is either the
+// path of a Navigator layer, the id of a group or software such as G0049 or
+// S0002, or a comma-separated list of technique ids. Groups and software are
+// resolved from the matrix, which may be nil for the other targets.
+func LoadProfile(target string, m *Matrix) (Profile, error) {
+	if info, err := os.Stat(target); err == nil && !info.IsDir() {
+		layer, err := LoadLayer(target)
+		if err != nil {
+			return Profile{}, err
+		}
+		return LayerProfile(layer), nil
+	}
+
+	if entityPattern.MatchString(target) {
+		if m == nil {
+			return Profile{}, fmt.Errorf("resolving %s needs an ATT&CK bundle", target)
+		}
+		entity, ok := m.Entity(target)
+		if !ok {
+			return Profile{}, fmt.Errorf("unknown ATT&CK group or software: %s", target)
+		}
+		return Profile{Name: fmt.Sprintf("%s (%s)", entity.Name, entity.ID), Techniques: entity.Techniques}, nil
+	}
+
+	var ids []string
+	for _, id := range strings.Split(target, ",") {
+		id = strings.TrimSpace(id)
+		ref, ok := ParseID(id)
+		if !ok || ref.Kind == TacticRef {
+			return Profile{}, fmt.Errorf("not a technique id: %s", id)
+		}
+		ids = append(ids, ref.ID)
+	}
+	return Profile{Name: target, Techniques: uniqueSorted(ids)}, nil
+}
+
+// LayerProfile returns the techniques of a Navigator layer. Techniques that
+// are disabled or scored zero are left out.
+func LayerProfile(layer Layer) Profile {
+	var ids []string
+	for _, technique := range layer.Techniques {
+		if technique.Enabled != nil && !*technique.Enabled {
+			continue
+		}
+		if technique.Score != nil && *technique.Score == 0 {
+			continue
+		}
+		if ref, ok := ParseID(technique.TechniqueID); ok && ref.Kind != TacticRef {
+			ids = append(ids, ref.ID)
+		}
+	}
+	return Profile{Name: layer.Name, Techniques: uniqueSorted(ids)}
+}
+
+func uniqueSorted(values []string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		if !seen[value] {
+			unique = append(unique, value)
+			seen[value] = true
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
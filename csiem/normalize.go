@@ -0,0 +1,27 @@
+package csiem
+
+import (
+	"strconv"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+func (r Rule) Normalize(path string, index int) model.Rule {
+	rule := model.Rule{
+		Format:   model.Csiem,
+		Path:     path,
+		Index:    index,
+		ID:       r.ID,
+		Title:    r.Name,
+		Tags:     r.Tags,
+		Severity: r.Level,
+		Author:   r.Author,
+		Date:     r.InsertDate,
+		Modified: r.LastUpdateDate,
+	}
+
+	rule.AddMetadata("datasource", r.DataSources...)
+	rule.AddMetadata("enabled", strconv.FormatBool(r.IsEnabled()))
+
+	return rule
+}
@@ -1,18 +1,102 @@
 package csiem
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+)
 
 type Rule struct {
-	Name string
+	ID          string `json:",omitempty"`
+	Name        string
+	Description string `json:",omitempty"`
+	Author      string `json:",omitempty"`
+
+	// Level is the severity of the rule, e.g. low, medium, high or critical.
+	Level   string `json:",omitempty"`
+	Enabled *bool  `json:",omitempty"`
+
+	Query       string   `json:",omitempty"`
+	Condition   string   `json:",omitempty"`
+	DataSources []string `json:",omitempty"`
+
+	InsertDate     string `json:",omitempty"`
+	LastUpdateDate string `json:",omitempty"`
 
 	Tags []string `json:",omitempty"`
 }
 
+var (
+	authorPattern     = regexp.MustCompile(`(?m)^Author:\s*(.+?)\s*$`)
+	sourcetypePattern = regexp.MustCompile(`sourcetype\s*=\s*'([^']+)'`)
+)
+
+func (r Rule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
 func ParseRule(input []byte) (Rule, error) {
 
 	rule := Rule{}
 
 	err := json.Unmarshal(input, &rule)
 
+	rule.complete()
+
 	return rule, err
 }
+
+// ParseRules parses a single rule, a JSON array of rules or a JSON Lines
+// export with one rule per line.
+func ParseRules(input []byte) ([]Rule, error) {
+	var rules []Rule
+
+	trimmed := bytes.TrimSpace(input)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &rules); err != nil {
+			return nil, err
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		for {
+			rule := Rule{}
+			err := decoder.Decode(&rule)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return rules, err
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	for i := range rules {
+		rules[i].complete()
+	}
+
+	return rules, nil
+}
+
+// complete fills the author and data sources from the description and query
+// when the rule document does not declare them explicitly.
+func (r *Rule) complete() {
+	if r.Author == "" {
+		if match := authorPattern.FindStringSubmatch(r.Description); match != nil {
+			r.Author = match[1]
+		}
+	}
+
+	if len(r.DataSources) == 0 {
+		seen := make(map[string]bool)
+		for _, match := range sourcetypePattern.FindAllStringSubmatch(r.Query, -1) {
+			source := strings.TrimSpace(match[1])
+			if !seen[source] {
+				r.DataSources = append(r.DataSources, source)
+				seen[source] = true
+			}
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	"github.com/mtnmunuklu/analyze-tags/csiem"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestParseRule(t *testing.T) {
@@ -33,3 +35,85 @@ func TestParseRule(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestParseRuleFields(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/chafer_activity.json")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rule, err := csiem.ParseRule(contents)
+	if err != nil {
+		t.Fatalf("error parsing rule: %v", err)
+	}
+
+	assert.Equal(t, "Chafer Activity", rule.Name)
+	assert.Equal(t, "high", rule.Level)
+	assert.Equal(t, "Florian Roth, Markus Neis, Jonhnathan Ribeiro, Daniil Yugoslavskiy, oscd.community", rule.Author)
+	assert.Equal(t, []string{"windows-sysmon"}, rule.DataSources)
+	assert.Equal(t, "2024-03-02T15:59:48Z", rule.InsertDate)
+	assert.True(t, rule.IsEnabled())
+	assert.Len(t, rule.Tags, 9)
+}
+
+func TestParseRules(t *testing.T) {
+	for _, name := range []string{"rules.json", "rules.jsonl"} {
+		t.Run(name, func(t *testing.T) {
+			contents, err := os.ReadFile(filepath.Join("./data/exports", name))
+			if err != nil {
+				t.Fatalf("failed reading test input: %v", err)
+			}
+
+			rules, err := csiem.ParseRules(contents)
+			if err != nil {
+				t.Fatalf("error parsing rules: %v", err)
+			}
+
+			if assert.Len(t, rules, 2) {
+				assert.Equal(t, "5f2c1a7e-3b0d-4c59-9a57-0d6b1e1f3a10", rules[0].ID)
+				assert.Equal(t, "high", rules[0].Level)
+				assert.True(t, rules[0].IsEnabled())
+				assert.Equal(t, "Suspicious PsExec Execution", rules[1].Name)
+				assert.False(t, rules[1].IsEnabled())
+			}
+		})
+	}
+
+	rules, err := csiem.ParseRules([]byte(`{"Name": "Single", "Tags": ["attack.t1059"]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []csiem.Rule{{Name: "Single", Tags: []string{"attack.t1059"}}}, rules)
+}
+
+func TestParseRulesDataSources(t *testing.T) {
+	contents, err := os.ReadFile("./data/exports/rules.json")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := csiem.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	assert.Equal(t, []string{"proxy"}, rules[0].DataSources)
+	assert.Equal(t, []string{"zeek-smb", "windows-security"}, rules[1].DataSources)
+}
+
+func TestNormalize(t *testing.T) {
+	contents, err := os.ReadFile("./data/exports/rules.json")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := csiem.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	normalized := rules[1].Normalize("rules.json", 1)
+	assert.Equal(t, model.Csiem, normalized.Format)
+	assert.Equal(t, "Suspicious PsExec Execution", normalized.Title)
+	assert.Equal(t, "medium", normalized.Severity)
+	assert.Equal(t, []string{"zeek-smb", "windows-security"}, normalized.Field("datasource"))
+	assert.Equal(t, []string{"false"}, normalized.Field("enabled"))
+}
@@ -0,0 +1,144 @@
+package detect
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+var (
+	yaraRulePattern   = regexp.MustCompile(`(?m)^\s*(?:(?:private|global)\s+)*rule\s+[A-Za-z_]\w*\s*(?::[\w\s]*)?\{`)
+	yaralPattern      = regexp.MustCompile(`(?m)^[ \t]*events[ \t]*:`)
+	sigmaTitlePattern = regexp.MustCompile(`(?m)^title:`)
+	sigmaBodyPattern  = regexp.MustCompile(`(?m)^(?:detection|correlation|logsource):|^action:\s*global`)
+	csiemFieldPattern = regexp.MustCompile(`"(?i:name)"\s*:`)
+	splunkNamePattern = regexp.MustCompile(`(?m)^name:`)
+	splunkBodyPattern = regexp.MustCompile(`(?m)^search:|^\s+analytic_story:`)
+	sentinelPattern   = regexp.MustCompile(`(?m)^(?:relevantTechniques|requiredDataConnectors|queryFrequency):`)
+	falcoPattern      = regexp.MustCompile(`(?m)^-\s+(?:rule|macro|list|required_engine_version):`)
+	nucleiIDPattern   = regexp.MustCompile(`(?m)^id:`)
+	nucleiInfoPattern = regexp.MustCompile(`(?m)^info:\s*$`)
+	stixPattern       = regexp.MustCompile(`"type"\s*:\s*"(?:bundle|indicator)"`)
+	armPattern        = regexp.MustCompile(`(?i)"type"\s*:\s*"[^"]*/alertRules"`)
+	elasticPattern    = regexp.MustCompile(`(?m)^\[rule\]\s*$`)
+	wazuhPattern      = regexp.MustCompile(`(?s)<group\s+name=.*<rule\s+[^>]*\bid=`)
+	suricataPattern   = regexp.MustCompile(`(?m)^\s*#?\s*(?:alert|drop|reject|pass|log)\s+\w+\s+\S+\s+\S+\s+(?:->|<>)\s+\S+\s+\S+\s*\(`)
+)
+
+// Detect returns the rule format of a file, looking at its extension first
+// and falling back to content heuristics for unknown extensions or inputs
+// without a path.
+func Detect(path string, content []byte) model.Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaral":
+		return model.YaraL
+	case ".yar", ".yara":
+		if isYaraL(content) {
+			return model.YaraL
+		}
+		return model.Yara
+	case ".yml", ".yaml":
+		switch {
+		case isSigma(content):
+			return model.Sigma
+		case isSplunk(content):
+			return model.Splunk
+		case sentinelPattern.Match(content):
+			return model.Sentinel
+		case falcoPattern.Match(content):
+			return model.Falco
+		case isNuclei(content):
+			return model.Nuclei
+		}
+		return model.Unknown
+	case ".json", ".jsonl", ".ndjson":
+		switch {
+		case armPattern.Match(content):
+			return model.Sentinel
+		case stixPattern.Match(content):
+			return model.Stix
+		case isCsiem(content):
+			return model.Csiem
+		}
+		return model.Unknown
+	case ".toml":
+		if elasticPattern.Match(content) {
+			return model.Elastic
+		}
+		return model.Unknown
+	case ".xml":
+		if wazuhPattern.Match(content) {
+			return model.Wazuh
+		}
+		return model.Unknown
+	case ".rules":
+		if suricataPattern.Match(content) {
+			return model.Suricata
+		}
+		return model.Unknown
+	}
+
+	return DetectContent(content)
+}
+
+func DetectContent(content []byte) model.Format {
+	switch {
+	case armPattern.Match(content):
+		return model.Sentinel
+	case stixPattern.Match(content):
+		return model.Stix
+	case isCsiem(content):
+		return model.Csiem
+	case isSigma(content):
+		return model.Sigma
+	case isSplunk(content):
+		return model.Splunk
+	case sentinelPattern.Match(content):
+		return model.Sentinel
+	case falcoPattern.Match(content):
+		return model.Falco
+	case isNuclei(content):
+		return model.Nuclei
+	case elasticPattern.Match(content):
+		return model.Elastic
+	case wazuhPattern.Match(content):
+		return model.Wazuh
+	case suricataPattern.Match(content):
+		return model.Suricata
+	case isYaraL(content):
+		return model.YaraL
+	case yaraRulePattern.Match(content):
+		return model.Yara
+	default:
+		return model.Unknown
+	}
+}
+
+// isYaraL reports whether the content holds YARA-L 2.0 rules, which share the
+// rule syntax of YARA but have an events section instead of strings.
+func isYaraL(content []byte) bool {
+	return yaraRulePattern.Match(content) && yaralPattern.Match(content)
+}
+
+func isSigma(content []byte) bool {
+	return sigmaTitlePattern.Match(content) && sigmaBodyPattern.Match(content)
+}
+
+func isSplunk(content []byte) bool {
+	return splunkNamePattern.Match(content) && splunkBodyPattern.Match(content)
+}
+
+func isNuclei(content []byte) bool {
+	return nucleiIDPattern.Match(content) && nucleiInfoPattern.Match(content)
+}
+
+func isCsiem(content []byte) bool {
+	trimmed := bytes.TrimSpace(content)
+	if !bytes.HasPrefix(trimmed, []byte("{")) && !bytes.HasPrefix(trimmed, []byte("[")) {
+		return false
+	}
+	return csiemFieldPattern.Match(trimmed)
+}
@@ -0,0 +1,50 @@
+package detect_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/detect"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	tests := map[string]model.Format{
+		"../sigma/data/rules/proxy_apt40.yml":                          model.Sigma,
+		"../sigma/data/rules/win_susp_sdelete_collection.yml":          model.Sigma,
+		"../yara/data/rules/malicious_author.yar":                      model.Yara,
+		"../csiem/data/rules/chafer_activity.json":                     model.Csiem,
+		"../csiem/data/exports/rules.jsonl":                            model.Csiem,
+		"../analytics/data/output/sigma/bar0_chart.html":               model.Unknown,
+		"../.github/workflows/go.yml":                                  model.Unknown,
+		"../sigma/data/rules/win_security_brute_force_correlation.yml": model.Sigma,
+		"../wazuh/data/rules/0095-sshd_rules.xml":                      model.Wazuh,
+		"../falco/data/rules/falco_rules.local.yaml":                   model.Falco,
+		"../yaral/data/rules/windows_process_rules.yaral":              model.YaraL,
+		"../nuclei/data/templates/CVE-2021-44228.yaml":                 model.Nuclei,
+		"../stix/data/bundles/indicators.json":                         model.Stix,
+		"../splunk/data/rules/powershell_4104_hunting.yml":             model.Splunk,
+	}
+
+	for path, expected := range tests {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed reading test input: %v", err)
+			}
+
+			assert.Equal(t, expected, detect.Detect(path, contents))
+		})
+	}
+}
+
+func TestDetectContent(t *testing.T) {
+	assert.Equal(t, model.Yara, detect.DetectContent([]byte("import \"pe\"\nprivate rule foo : bar {\n condition: true\n}")))
+	assert.Equal(t, model.Sigma, detect.DetectContent([]byte("title: Foo\nlogsource:\n  product: windows\n")))
+	assert.Equal(t, model.YaraL, detect.DetectContent([]byte("rule foo {\n meta:\n  author = \"x\"\n events:\n  $e.metadata.event_type = \"USER_LOGIN\"\n condition:\n  $e\n}")))
+	assert.Equal(t, model.Csiem, detect.DetectContent([]byte(`[{"Name": "Foo", "Tags": []}]`)))
+	assert.Equal(t, model.Suricata, detect.DetectContent([]byte(`alert tcp any any -> any 80 (msg:"foo"; sid:1;)`)))
+	assert.Equal(t, model.Unknown, detect.DetectContent([]byte("just some text")))
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// diffInputs returns the files of the old and the new ruleset compared by
+// the diff mode. The old ruleset is the -diff directory, or the -filepath
+// directory at the -diff git revision. A revision range such as main..HEAD
+// compares -filepath at both revisions, and main...HEAD compares HEAD with
+// the merge base of main and HEAD as git diff does. Otherwise the new
+// ruleset is the -filepath directory as it is on disk. Paths are relative to
+// the compared directories so that rules without an id match by location.
+func diffInputs(base string, root string) (map[string][]byte, map[string][]byte, error) {
+	if info, err := os.Stat(base); err == nil && info.IsDir() {
+		oldFiles, err := readDirectory(base)
+		if err != nil {
+			return nil, nil, err
+		}
+		newFiles, err := readDirectory(root)
+		return oldFiles, newFiles, err
+	}
+
+	oldRevision, newRevision, isRange := strings.Cut(base, "..")
+	if isRange && strings.HasPrefix(newRevision, ".") {
+		newRevision = strings.TrimPrefix(newRevision, ".")
+		mergeBase, err := git(root, "merge-base", revisionOrHead(oldRevision), revisionOrHead(newRevision))
+		if err != nil {
+			return nil, nil, err
+		}
+		oldRevision = strings.TrimSpace(string(mergeBase))
+	}
+
+	oldFiles, err := readRevision(root, oldRevision)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !isRange {
+		newFiles, err := readDirectory(root)
+		return oldFiles, newFiles, err
+	}
+	newFiles, err := readRevision(root, newRevision)
+	return oldFiles, newFiles, err
+}
+
+func readDirectory(root string) (map[string][]byte, error) {
+	fileContents := make(map[string][]byte)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		fileContents[filepath.ToSlash(relative)] = content
+		return nil
+	})
+	return fileContents, err
+}
+
+// readRevision returns the files of a directory of a git work tree at a
+// revision. The directory is read in one pass from a tar archive of the
+// revision, which git limits to the directory and its subdirectories. A
+// directory that does not exist at the revision has no files.
+func readRevision(root string, revision string) (map[string][]byte, error) {
+	fileContents := make(map[string][]byte)
+
+	entries, err := git(root, "ls-tree", revisionOrHead(revision), "--", ".")
+	if err != nil || len(entries) == 0 {
+		return fileContents, err
+	}
+
+	archive, err := git(root, "archive", "--format=tar", revisionOrHead(revision), "--", ".")
+	if err != nil {
+		return nil, err
+	}
+
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		fileContents[header.Name] = content
+	}
+	return fileContents, nil
+}
+
+func revisionOrHead(revision string) string {
+	if revision == "" {
+		return "HEAD"
+	}
+	return revision
+}
+
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
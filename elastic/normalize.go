@@ -0,0 +1,68 @@
+package elastic
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+const attackFramework = "MITRE ATT&CK"
+
+// ThreatTags returns the MITRE ATT&CK mappings of the rule in the Sigma tag
+// vocabulary, e.g. attack.execution, attack.t1059 and attack.t1059.001, so
+// Elastic coverage can be compared with Sigma coverage.
+func (r Rule) ThreatTags() []string {
+	var tags []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		if !seen[tag] {
+			tags = append(tags, tag)
+			seen[tag] = true
+		}
+	}
+
+	for _, threat := range r.Threat {
+		if threat.Framework != "" && threat.Framework != attackFramework {
+			continue
+		}
+
+		if threat.Tactic.Name != "" {
+			add("attack." + strings.ReplaceAll(strings.ToLower(threat.Tactic.Name), " ", "_"))
+		}
+		for _, technique := range threat.Technique {
+			add("attack." + strings.ToLower(technique.ID))
+			for _, subtechnique := range technique.Subtechnique {
+				add("attack." + strings.ToLower(subtechnique.ID))
+			}
+		}
+	}
+
+	return tags
+}
+
+func (f RuleFile) Normalize(path string, index int) model.Rule {
+	rule := model.Rule{
+		Format:   model.Elastic,
+		Path:     path,
+		Index:    index,
+		ID:       f.Rule.RuleID,
+		Title:    f.Rule.Name,
+		Tags:     append(append([]string(nil), f.Rule.Tags...), f.Rule.ThreatTags()...),
+		Severity: f.Rule.Severity,
+		Author:   strings.Join(f.Rule.Author, ", "),
+		Date:     f.Metadata.CreationDate,
+		Modified: f.Metadata.UpdatedDate,
+	}
+
+	rule.AddMetadata("type", f.Rule.Type)
+	rule.AddMetadata("language", f.Rule.Language)
+	rule.AddMetadata("maturity", f.Metadata.Maturity)
+	rule.AddMetadata("integration", f.Metadata.Integration...)
+	rule.AddMetadata("index", f.Rule.Index...)
+	if f.Rule.RiskScore > 0 {
+		rule.AddMetadata("risk_score", strconv.Itoa(f.Rule.RiskScore))
+	}
+
+	return rule
+}
@@ -0,0 +1,62 @@
+package elastic
+
+import (
+	"github.com/BurntSushi/toml"
+)
+
+// RuleFile is an Elastic detection rule as stored in the detection-rules
+// repository, with a [metadata] and a [rule] table.
+type RuleFile struct {
+	Metadata Metadata `toml:"metadata"`
+	Rule     Rule     `toml:"rule"`
+}
+
+type Metadata struct {
+	CreationDate string   `toml:"creation_date"`
+	UpdatedDate  string   `toml:"updated_date"`
+	Maturity     string   `toml:"maturity"`
+	Integration  []string `toml:"integration"`
+}
+
+type Rule struct {
+	RuleID      string   `toml:"rule_id"`
+	Name        string   `toml:"name"`
+	Description string   `toml:"description"`
+	Author      []string `toml:"author"`
+	Type        string   `toml:"type"`
+	Language    string   `toml:"language"`
+	Index       []string `toml:"index"`
+	Query       string   `toml:"query"`
+	Severity    string   `toml:"severity"`
+	RiskScore   int      `toml:"risk_score"`
+	References  []string `toml:"references"`
+
+	Tags   []string `toml:"tags"`
+	Threat []Threat `toml:"threat"`
+}
+
+type Threat struct {
+	Framework string      `toml:"framework"`
+	Tactic    Mapping     `toml:"tactic"`
+	Technique []Technique `toml:"technique"`
+}
+
+type Mapping struct {
+	ID        string `toml:"id"`
+	Name      string `toml:"name"`
+	Reference string `toml:"reference"`
+}
+
+type Technique struct {
+	Mapping
+	Subtechnique []Mapping `toml:"subtechnique"`
+}
+
+func ParseRule(input []byte) (RuleFile, error) {
+
+	rule := RuleFile{}
+
+	err := toml.Unmarshal(input, &rule)
+
+	return rule, err
+}
@@ -0,0 +1,73 @@
+package elastic_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/elastic"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRuleFields(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/execution_suspicious_powershell_imgload.toml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	file, err := elastic.ParseRule(contents)
+	if err != nil {
+		t.Fatalf("error parsing rule: %v", err)
+	}
+
+	assert.Equal(t, "852c1f19-68e8-43a6-9dce-340771fe1be3", file.Rule.RuleID)
+	assert.Equal(t, "Suspicious PowerShell Engine ImageLoad", file.Rule.Name)
+	assert.Equal(t, "medium", file.Rule.Severity)
+	assert.Equal(t, 47, file.Rule.RiskScore)
+	assert.Equal(t, "2020/11/17", file.Metadata.CreationDate)
+	assert.Equal(t, []string{
+		"Domain: Endpoint",
+		"OS: Windows",
+		"Use Case: Threat Detection",
+		"Tactic: Execution",
+		"Data Source: Elastic Defend",
+	}, file.Rule.Tags)
+
+	if assert.Len(t, file.Rule.Threat, 2) {
+		threat := file.Rule.Threat[0]
+		assert.Equal(t, "TA0002", threat.Tactic.ID)
+		assert.Equal(t, "T1059", threat.Technique[0].ID)
+		assert.Equal(t, "T1059.001", threat.Technique[0].Subtechnique[0].ID)
+	}
+
+	assert.Equal(t, []string{"attack.execution", "attack.t1059", "attack.t1059.001", "attack.command_and_control"}, file.Rule.ThreatTags())
+}
+
+func TestNormalize(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/execution_suspicious_powershell_imgload.toml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	file, err := elastic.ParseRule(contents)
+	if err != nil {
+		t.Fatalf("error parsing rule: %v", err)
+	}
+
+	normalized := file.Normalize("rule.toml", 0)
+	assert.Equal(t, model.Elastic, normalized.Format)
+	assert.Equal(t, "Elastic", normalized.Author)
+	assert.Equal(t, []string{
+		"Domain: Endpoint",
+		"OS: Windows",
+		"Use Case: Threat Detection",
+		"Tactic: Execution",
+		"Data Source: Elastic Defend",
+		"attack.execution",
+		"attack.t1059",
+		"attack.t1059.001",
+		"attack.command_and_control",
+	}, normalized.Tags)
+	assert.Equal(t, []string{"47"}, normalized.Field("risk_score"))
+	assert.Equal(t, []string{"endpoint"}, normalized.Field("integration"))
+}
@@ -0,0 +1,29 @@
+package falco
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+func (r Rule) Normalize(path string, index int) model.Rule {
+	rule := model.Rule{
+		Format:   model.Falco,
+		Path:     path,
+		Index:    index,
+		Title:    r.Name,
+		Tags:     r.Tags,
+		Severity: strings.ToLower(r.Priority),
+	}
+
+	source := r.Source
+	if source == "" {
+		source = "syscall"
+	}
+
+	rule.AddMetadata("source", source)
+	rule.AddMetadata("enabled", strconv.FormatBool(r.IsEnabled()))
+
+	return rule
+}
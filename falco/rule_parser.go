@@ -0,0 +1,57 @@
+package falco
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// Rule is a rule item of a Falco rules file. Items that modify an earlier
+// rule instead of defining one set Append, Override or only Enabled.
+type Rule struct {
+	Name      string            `yaml:"rule"`
+	Desc      string            `yaml:",omitempty" json:",omitempty"`
+	Condition string            `yaml:",omitempty" json:",omitempty"`
+	Output    string            `yaml:",omitempty" json:",omitempty"`
+	Priority  string            `yaml:",omitempty" json:",omitempty"`
+	Source    string            `yaml:",omitempty" json:",omitempty"`
+	Tags      []string          `yaml:",omitempty" json:",omitempty"`
+	Enabled   *bool             `yaml:",omitempty" json:",omitempty"`
+	Append    bool              `yaml:",omitempty" json:",omitempty"`
+	Override  map[string]string `yaml:",omitempty" json:",omitempty"`
+}
+
+// item is any top level entry of a rules file: a rule, a macro, a list or
+// a version requirement.
+type item struct {
+	Rule  `yaml:",inline"`
+	Macro string
+	List  string
+}
+
+// IsEnabled returns true unless the rule is explicitly disabled.
+func (r Rule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// Modifies reports whether the item changes a rule defined earlier, either
+// with append: true, with an override section or by only toggling enabled.
+func (r Rule) Modifies() bool {
+	return r.Append || len(r.Override) > 0 || (r.Enabled != nil && r.Condition == "" && r.Output == "")
+}
+
+// ParseRules returns the rule items of a Falco rules file in order. Macros,
+// lists and version requirements are skipped.
+func ParseRules(input []byte) ([]Rule, error) {
+	var items []item
+	if err := yaml.Unmarshal(input, &items); err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	for _, item := range items {
+		if item.Name != "" {
+			rules = append(rules, item.Rule)
+		}
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,88 @@
+package falco_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/falco"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRules(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/falco_rules.yaml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := falco.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	if !assert.Len(t, rules, 3) {
+		return
+	}
+
+	rule := rules[0]
+	assert.Equal(t, "Terminal shell in container", rule.Name)
+	assert.Equal(t, "NOTICE", rule.Priority)
+	assert.Equal(t, []string{"maturity_stable", "container", "shell", "mitre_execution", "T1059"}, rule.Tags)
+	assert.True(t, rule.IsEnabled())
+	assert.False(t, rule.Modifies())
+
+	assert.False(t, rules[2].IsEnabled())
+	assert.False(t, rules[2].Modifies())
+}
+
+func TestRuleset(t *testing.T) {
+	ruleset := falco.NewRuleset()
+
+	// Local overrides sort before the default rules and are applied once
+	// every file has been added.
+	for _, path := range []string{"./data/rules/falco_rules.local.yaml", "./data/rules/falco_rules.yaml"} {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed reading test input: %v", err)
+		}
+		if err := ruleset.Add(path, contents); err != nil {
+			t.Fatalf("error parsing rules: %v", err)
+		}
+	}
+
+	rules, err := ruleset.Rules()
+	if err != nil {
+		t.Fatalf("error resolving rules: %v", err)
+	}
+
+	if !assert.Len(t, rules, 3) {
+		return
+	}
+
+	assert.Contains(t, rules[0].Condition, "proc.tty != 0 and not user_known_shell_in_container")
+	assert.Equal(t, "CRITICAL", rules[1].Priority)
+	assert.Equal(t, []string{"maturity_stable", "host", "container", "filesystem", "mitre_credential_access", "T1555", "T1003"}, rules[1].Tags)
+	assert.True(t, rules[2].IsEnabled())
+
+	normalized, err := ruleset.Normalize()
+	if err != nil {
+		t.Fatalf("error resolving rules: %v", err)
+	}
+
+	assert.Equal(t, model.Falco, normalized[1].Format)
+	assert.Equal(t, "./data/rules/falco_rules.yaml#1", normalized[1].Location())
+	assert.Equal(t, "critical", normalized[1].Severity)
+	assert.Equal(t, []string{"syscall"}, normalized[1].Field("source"))
+}
+
+func TestRulesetUndefined(t *testing.T) {
+	ruleset := falco.NewRuleset()
+	err := ruleset.Add("local.yaml", []byte("- rule: Missing\n  append: true\n  condition: and true\n"))
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	rules, err := ruleset.Rules()
+	assert.Empty(t, rules)
+	assert.EqualError(t, err, `modified rules are not defined: "Missing" (local.yaml#0)`)
+}
@@ -0,0 +1,163 @@
+package falco
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+// Ruleset collects the rules of several Falco rules files and resolves the
+// items that append to or override rules defined in another file, the way
+// Falco does when it loads the files in order.
+type Ruleset struct {
+	entries []entry
+}
+
+type entry struct {
+	path  string
+	index int
+	rule  Rule
+}
+
+func NewRuleset() *Ruleset {
+	return &Ruleset{}
+}
+
+// Add parses a rules file and adds its rule items to the ruleset.
+func (s *Ruleset) Add(path string, input []byte) error {
+	rules, err := ParseRules(input)
+	if err != nil {
+		return err
+	}
+
+	for i, rule := range rules {
+		s.entries = append(s.entries, entry{path: path, index: i, rule: rule})
+	}
+	return nil
+}
+
+// Rules returns the resolved rules. A rule defined again replaces the earlier
+// definition, and modifying items are applied to the rule they name. Items
+// that name a rule defined only in a later file are applied once every file
+// has been read, so local override files do not depend on file order. The
+// error lists the rules that are modified but never defined.
+func (s *Ruleset) Rules() ([]Rule, error) {
+	entries, err := s.resolve()
+
+	rules := make([]Rule, 0, len(entries))
+	for _, e := range entries {
+		rules = append(rules, e.rule)
+	}
+	return rules, err
+}
+
+// Normalize returns the resolved rules as model rules located in the file
+// that defines them.
+func (s *Ruleset) Normalize() ([]model.Rule, error) {
+	entries, err := s.resolve()
+
+	rules := make([]model.Rule, 0, len(entries))
+	for _, e := range entries {
+		rules = append(rules, e.rule.Normalize(e.path, e.index))
+	}
+	return rules, err
+}
+
+func (s *Ruleset) resolve() ([]entry, error) {
+	var resolved []entry
+	byName := make(map[string]int)
+
+	apply := func(e entry) bool {
+		i, defined := byName[e.rule.Name]
+		switch {
+		case !e.rule.Modifies() && defined:
+			resolved[i] = e
+		case !e.rule.Modifies():
+			byName[e.rule.Name] = len(resolved)
+			resolved = append(resolved, e)
+		case defined:
+			resolved[i].rule = resolved[i].rule.apply(e.rule)
+		default:
+			return false
+		}
+		return true
+	}
+
+	var pending []entry
+	for _, e := range s.entries {
+		if !apply(e) {
+			pending = append(pending, e)
+		}
+	}
+
+	var undefined []string
+	for _, e := range pending {
+		if !apply(e) {
+			undefined = append(undefined, fmt.Sprintf("%q (%s#%d)", e.rule.Name, e.path, e.index))
+		}
+	}
+
+	if len(undefined) > 0 {
+		return resolved, fmt.Errorf("modified rules are not defined: %s", strings.Join(undefined, ", "))
+	}
+	return resolved, nil
+}
+
+// apply returns the rule changed by a modifying item. Legacy append items
+// extend the condition, override items append to or replace the fields they
+// list and other items only set enabled.
+func (r Rule) apply(m Rule) Rule {
+	switch {
+	case m.Append:
+		r.Condition = joinText(r.Condition, m.Condition)
+		return r
+	case len(m.Override) == 0:
+		r.Enabled = m.Enabled
+		return r
+	}
+
+	for field, mode := range m.Override {
+		appending := mode == "append"
+		switch field {
+		case "condition":
+			r.Condition = mergeText(r.Condition, m.Condition, appending)
+		case "output":
+			r.Output = mergeText(r.Output, m.Output, appending)
+		case "desc":
+			r.Desc = mergeText(r.Desc, m.Desc, appending)
+		case "tags":
+			if appending {
+				r.Tags = append(append([]string(nil), r.Tags...), m.Tags...)
+			} else {
+				r.Tags = m.Tags
+			}
+		case "priority":
+			r.Priority = m.Priority
+		case "source":
+			r.Source = m.Source
+		case "enabled":
+			r.Enabled = m.Enabled
+		}
+	}
+	return r
+}
+
+func mergeText(current, value string, appending bool) string {
+	if appending {
+		return joinText(current, value)
+	}
+	return value
+}
+
+func joinText(current, value string) string {
+	current = strings.TrimSpace(current)
+	value = strings.TrimSpace(value)
+	if current == "" {
+		return value
+	}
+	if value == "" {
+		return current
+	}
+	return current + " " + value
+}
@@ -3,6 +3,7 @@ module github.com/mtnmunuklu/analyze-tags
 go 1.20
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/VirusTotal/gyp v0.9.0
 	github.com/go-echarts/go-echarts/v2 v2.3.3
 	github.com/stretchr/testify v1.9.0
@@ -0,0 +1,270 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/attack"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/mtnmunuklu/analyze-tags/taxonomy"
+)
+
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+// Check is a single lint check. ID is the rule id reported in SARIF.
+type Check struct {
+	ID          string
+	Description string
+	Severity    Severity
+}
+
+var (
+	NoTags          = Check{"no-tags", "The rule has no tags.", Warning}
+	DuplicateTag    = Check{"duplicate-tag", "The rule has the same tag more than once.", Warning}
+	MalformedTag    = Check{"malformed-attack-tag", "An ATT&CK tag is not of the form attack.tNNNN or attack.tNNNN.NNN.", Error}
+	UnknownID       = Check{"unknown-attack-id", "An ATT&CK tag refers to a tactic, technique, group or software that is not in the ATT&CK bundle.", Error}
+	Deprecated      = Check{"deprecated-technique", "An ATT&CK tag refers to a deprecated technique.", Warning}
+	Revoked         = Check{"revoked-technique", "An ATT&CK tag refers to a revoked technique.", Error}
+	MissingTactic   = Check{"missing-tactic", "The rule is tagged with a technique but with none of its tactics.", Warning}
+	NonCanonicalTag = Check{"non-canonical-tag", "A tag is not in the canonical form of the taxonomy.", Warning}
+)
+
+// Checks lists every check in the order they run.
+var Checks = []Check{NoTags, DuplicateTag, MalformedTag, UnknownID, Deprecated, Revoked, MissingTactic, NonCanonicalTag}
+
+// Finding is a problem found in the tags of a rule. Tag is empty for
+// findings about the rule as a whole.
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Tag      string   `json:"tag,omitempty"`
+	Rule     string   `json:"rule"`
+	Title    string   `json:"title"`
+	Path     string   `json:"path"`
+	Location string   `json:"location"`
+}
+
+var (
+	attackTagPattern = regexp.MustCompile(`^attack\.t\d{4}(?:\.\d{3})?$`)
+	attackIDPattern  = regexp.MustCompile(`^(?i)(?:ta?|g|s)\d`)
+	entityTagPattern = regexp.MustCompile(`^[gs]\d{4}$`)
+)
+
+// Linter checks the tags of rules as they were parsed, before any ATT&CK
+// normalization or taxonomy is applied. The ATT&CK checks that need the
+// bundle are skipped when Attack is nil, and the canonical form of the tags
+// is only checked when Taxonomy is set.
+type Linter struct {
+	Attack   *attack.Matrix
+	Taxonomy *taxonomy.Taxonomy
+}
+
+// Lint returns the findings of every rule in the order of the rules.
+func (l *Linter) Lint(rules []model.Rule) []Finding {
+	var findings []Finding
+	for _, rule := range rules {
+		findings = append(findings, l.LintRule(rule)...)
+	}
+	return findings
+}
+
+// LintRule returns the findings of a single rule. Repeated tags are reported
+// once and not checked again.
+func (l *Linter) LintRule(rule model.Rule) []Finding {
+	var findings []Finding
+	report := func(check Check, tag string, format string, args ...interface{}) {
+		findings = append(findings, Finding{
+			Check:    check.ID,
+			Severity: check.Severity,
+			Message:  fmt.Sprintf(format, args...),
+			Tag:      tag,
+			Rule:     rule.Key(),
+			Title:    rule.Title,
+			Path:     rule.Path,
+			Location: rule.Location(),
+		})
+	}
+
+	if len(rule.Tags) == 0 {
+		report(NoTags, "", "rule %q has no tags", rule.Title)
+		return findings
+	}
+
+	seen := make(map[string]string)
+	for _, tag := range rule.Tags {
+		key := l.canonical(tag)
+		if first, ok := seen[key]; ok {
+			if first == tag {
+				report(DuplicateTag, tag, "tag %q is repeated", tag)
+			} else {
+				report(DuplicateTag, tag, "tag %q is the same as %q", tag, first)
+			}
+			continue
+		}
+		seen[key] = tag
+
+		l.lintAttackTag(tag, report)
+
+		if l.Taxonomy != nil {
+			if canonical := l.Taxonomy.Tag(tag); canonical == "" {
+				report(NonCanonicalTag, tag, "tag %q is removed by the taxonomy", tag)
+			} else if canonical != tag {
+				report(NonCanonicalTag, tag, "tag %q is not canonical, use %q", tag, canonical)
+			}
+		}
+	}
+
+	l.lintTactics(rule, report)
+
+	return findings
+}
+
+type reporter func(check Check, tag string, format string, args ...interface{})
+
+// canonical returns the form of a tag used to find duplicates: the
+// canonical form of the taxonomy, or the tag in lower case without one.
+func (l *Linter) canonical(tag string) string {
+	if l.Taxonomy != nil {
+		if canonical := l.Taxonomy.Tag(tag); canonical != "" {
+			return canonical
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// lintAttackTag checks that tags of the Sigma attack namespace are well
+// formed and that the ATT&CK ids of every format exist in the bundle.
+func (l *Linter) lintAttackTag(tag string, report reporter) {
+	value, isAttack := cutAttackTag(tag)
+	if isAttack && attackIDPattern.MatchString(value) {
+		if entityTagPattern.MatchString(value) {
+			if _, found := l.entity(value); !found {
+				report(UnknownID, tag, "unknown ATT&CK group or software %q", tag)
+			}
+			return
+		}
+		if !attackTagPattern.MatchString(tag) {
+			report(MalformedTag, tag, "malformed ATT&CK tag %q, expected attack.tNNNN or attack.tNNNN.NNN", tag)
+			return
+		}
+	}
+
+	if l.Attack == nil {
+		return
+	}
+
+	ref, ok := attack.ParseID(tag)
+	if !ok {
+		if _, found := l.Attack.Resolve(tag); isAttack && !found {
+			report(UnknownID, tag, "unknown ATT&CK tactic %q", tag)
+		}
+		return
+	}
+
+	if ref.Kind == attack.TacticRef {
+		if _, found := l.Attack.Tactic(ref.ID); !found {
+			report(UnknownID, tag, "unknown ATT&CK tactic %s", ref.ID)
+		}
+		return
+	}
+
+	technique, found := l.Attack.Technique(ref.ID)
+	switch {
+	case !found:
+		report(UnknownID, tag, "unknown ATT&CK technique %s", ref.ID)
+	case technique.Revoked && technique.RevokedBy != "":
+		report(Revoked, tag, "technique %s is revoked by %s", ref.ID, technique.RevokedBy)
+	case technique.Revoked:
+		report(Revoked, tag, "technique %s is revoked", ref.ID)
+	case technique.Deprecated:
+		report(Deprecated, tag, "technique %s (%s) is deprecated", ref.ID, technique.Name)
+	}
+}
+
+// entity looks up a group or software, which always succeeds without a
+// bundle.
+func (l *Linter) entity(id string) (attack.Entity, bool) {
+	if l.Attack == nil {
+		return attack.Entity{}, true
+	}
+	return l.Attack.Entity(id)
+}
+
+// cutAttackTag returns the value of a tag of the Sigma attack namespace.
+func cutAttackTag(tag string) (string, bool) {
+	if len(tag) < len("attack.") || !strings.EqualFold(tag[:len("attack.")], "attack.") {
+		return "", false
+	}
+	return tag[len("attack."):], true
+}
+
+// lintTactics reports the techniques of a rule that come without any of
+// their tactics. Without a bundle the tactics of a technique are unknown,
+// so only rules without any tactic tag are reported.
+func (l *Linter) lintTactics(rule model.Rule, report reporter) {
+	if l.Attack == nil {
+		hasTechnique, hasTactic := false, false
+		for _, tag := range rule.Tags {
+			value, ok := cutAttackTag(tag)
+			switch {
+			case !ok:
+			case attackIDPattern.MatchString(value):
+				ref, ok := attack.ParseID(tag)
+				hasTechnique = hasTechnique || (ok && ref.Kind != attack.TacticRef)
+			default:
+				hasTactic = true
+			}
+		}
+		if hasTechnique && !hasTactic {
+			report(MissingTactic, "", "rule %q has technique tags but no tactic tag", rule.Title)
+		}
+		return
+	}
+
+	tactics := make(map[string]bool)
+	refs := l.Attack.Refs(rule.Tags)
+	for _, ref := range refs {
+		if ref.Kind == attack.TacticRef {
+			if tactic, ok := l.Attack.Tactic(ref.ID); ok {
+				tactics[tactic.ShortName] = true
+			}
+		}
+	}
+
+	for _, ref := range refs {
+		technique, ok := l.Attack.Technique(ref.ID)
+		if ref.Kind == attack.TacticRef || !ok || technique.Revoked || technique.Deprecated || len(technique.Tactics) == 0 {
+			continue
+		}
+
+		covered := false
+		var names []string
+		for _, shortName := range technique.Tactics {
+			if tactic, ok := l.Attack.Tactic(shortName); ok {
+				covered = covered || tactics[tactic.ShortName]
+				names = append(names, l.Attack.Tag(attack.Ref{Kind: attack.TacticRef, ID: tactic.ID}))
+			}
+		}
+		if !covered {
+			report(MissingTactic, "", "technique %s has none of its tactics: %s", ref.ID, strings.Join(names, ", "))
+		}
+	}
+}
+
+// Count returns the number of findings of a severity.
+func Count(findings []Finding, severity Severity) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,154 @@
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/attack"
+	"github.com/mtnmunuklu/analyze-tags/lint"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/mtnmunuklu/analyze-tags/taxonomy"
+	"github.com/stretchr/testify/assert"
+)
+
+func checks(findings []lint.Finding) []string {
+	var ids []string
+	for _, finding := range findings {
+		ids = append(ids, finding.Check+" "+finding.Tag)
+	}
+	return ids
+}
+
+func TestLintWithoutBundle(t *testing.T) {
+	linter := lint.Linter{}
+
+	findings := linter.LintRule(model.Rule{Title: "Rule1", Path: "rules/rule1.yml"})
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, lint.Finding{
+			Check:    "no-tags",
+			Severity: lint.Warning,
+			Message:  `rule "Rule1" has no tags`,
+			Rule:     "rules/rule1.yml#0",
+			Title:    "Rule1",
+			Path:     "rules/rule1.yml",
+			Location: "rules/rule1.yml#0",
+		}, findings[0])
+	}
+
+	findings = linter.LintRule(model.Rule{Tags: []string{"attack.t1059", "attack.T1059", "attack.t105", "attack.t1059/001", "attack.g0049", "attack.t9999", "cve.2021-44228"}})
+	assert.Equal(t, []string{
+		"duplicate-tag attack.T1059",
+		"malformed-attack-tag attack.t105",
+		"malformed-attack-tag attack.t1059/001",
+		"missing-tactic ",
+	}, checks(findings))
+	assert.Equal(t, `tag "attack.T1059" is the same as "attack.t1059"`, findings[0].Message)
+
+	findings = linter.LintRule(model.Rule{Tags: []string{"attack.execution", "attack.t1059"}})
+	assert.Empty(t, findings)
+}
+
+func TestLintWithBundle(t *testing.T) {
+	matrix, err := attack.Load("../attack/data/enterprise-attack.json")
+	if err != nil {
+		t.Fatalf("error loading bundle: %v", err)
+	}
+
+	linter := lint.Linter{Attack: matrix}
+
+	findings := linter.LintRule(model.Rule{Tags: []string{
+		"attack.execution", "attack.t1059.001", "attack.t9999", "attack.t1043", "attack.t1064",
+		"attack.g0049", "attack.g9999", "attack.exfiltrate", "mitre:T1110",
+	}})
+	assert.Equal(t, []string{
+		"unknown-attack-id attack.t9999",
+		"deprecated-technique attack.t1043",
+		"revoked-technique attack.t1064",
+		"unknown-attack-id attack.g9999",
+		"unknown-attack-id attack.exfiltrate",
+		"missing-tactic ",
+	}, checks(findings))
+	assert.Equal(t, "technique T1064 is revoked by T1059", findings[2].Message)
+	assert.Equal(t, "technique T1110 has none of its tactics: attack.credential_access", findings[5].Message)
+
+	findings = linter.LintRule(model.Rule{Tags: []string{"mitre_persistence", "T1053.005"}})
+	assert.Empty(t, findings)
+}
+
+func TestLintWithTaxonomy(t *testing.T) {
+	tax, err := taxonomy.Load("../taxonomy/data/taxonomy.yml")
+	if err != nil {
+		t.Fatalf("error loading taxonomy: %v", err)
+	}
+
+	linter := lint.Linter{Taxonomy: tax}
+
+	findings := linter.LintRule(model.Rule{Tags: []string{"attack.execution", "mitre_execution", "T1059", "maturity_stable"}})
+	assert.Equal(t, []string{
+		"duplicate-tag mitre_execution",
+		"non-canonical-tag T1059",
+		"non-canonical-tag maturity_stable",
+	}, checks(findings))
+	assert.Equal(t, `tag "T1059" is not canonical, use "attack.t1059"`, findings[1].Message)
+}
+
+func TestWrite(t *testing.T) {
+	findings := (&lint.Linter{}).Lint([]model.Rule{
+		{Title: "Rule1", Path: "rules/rule1.yml", Tags: []string{"attack.t105"}},
+		{Title: "Rule2", Path: "rules/rule2.yml", Index: 1},
+	})
+	assert.Equal(t, 1, lint.Count(findings, lint.Error))
+	assert.Equal(t, 1, lint.Count(findings, lint.Warning))
+
+	var text bytes.Buffer
+	assert.NoError(t, lint.Write(&text, lint.Text, findings))
+	assert.Equal(t, `rules/rule1.yml#0: error: malformed ATT&CK tag "attack.t105", expected attack.tNNNN or attack.tNNNN.NNN [malformed-attack-tag]
+rules/rule2.yml#1: warning: rule "Rule2" has no tags [no-tags]
+1 errors, 1 warnings
+`, text.String())
+
+	var output bytes.Buffer
+	assert.NoError(t, lint.Write(&output, lint.JSON, findings))
+	var decoded []lint.Finding
+	if assert.NoError(t, json.Unmarshal(output.Bytes(), &decoded)) {
+		assert.Equal(t, findings, decoded)
+	}
+
+	output.Reset()
+	assert.NoError(t, lint.Write(&output, lint.SARIF, findings))
+	log := struct {
+		Version string
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct{ ID string }
+				}
+			}
+			Results []struct {
+				RuleID    string
+				RuleIndex int
+				Level     string
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+					}
+				}
+			}
+		}
+	}{}
+	if assert.NoError(t, json.Unmarshal(output.Bytes(), &log)) && assert.Len(t, log.Runs, 1) {
+		run := log.Runs[0]
+		assert.Equal(t, "2.1.0", log.Version)
+		assert.Len(t, run.Tool.Driver.Rules, len(lint.Checks))
+		if assert.Len(t, run.Results, 2) {
+			assert.Equal(t, "malformed-attack-tag", run.Results[0].RuleID)
+			assert.Equal(t, "malformed-attack-tag", run.Tool.Driver.Rules[run.Results[0].RuleIndex].ID)
+			assert.Equal(t, "error", run.Results[0].Level)
+			assert.Equal(t, "rules/rule1.yml", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+		}
+	}
+
+	_, err := lint.FindFormat("xml")
+	assert.EqualError(t, err, "unsupported lint format: xml")
+}
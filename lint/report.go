@@ -0,0 +1,191 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+type Format string
+
+const (
+	Text  Format = "text"
+	JSON  Format = "json"
+	SARIF Format = "sarif"
+)
+
+func FindFormat(format string) (Format, error) {
+	switch format {
+	case "text":
+		return Text, nil
+	case "json":
+		return JSON, nil
+	case "sarif":
+		return SARIF, nil
+	default:
+		return "", fmt.Errorf("unsupported lint format: %s", format)
+	}
+}
+
+// Extension returns the file extension of a report in the format.
+func (f Format) Extension() string {
+	if f == Text {
+		return "txt"
+	}
+	return string(f)
+}
+
+// Write writes the findings in the given format.
+func Write(w io.Writer, format Format, findings []Finding) error {
+	switch format {
+	case Text:
+		return WriteText(w, findings)
+	case JSON:
+		return WriteJSON(w, findings)
+	case SARIF:
+		return WriteSARIF(w, findings)
+	default:
+		return fmt.Errorf("unsupported lint format: %s", format)
+	}
+}
+
+// WriteText writes one line per finding followed by the number of errors
+// and warnings.
+func WriteText(w io.Writer, findings []Finding) error {
+	for _, finding := range findings {
+		if _, err := fmt.Fprintf(w, "%s: %s: %s [%s]\n", finding.Location, finding.Severity, finding.Message, finding.Check); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d errors, %d warnings\n", Count(findings, Error), Count(findings, Warning))
+	return err
+}
+
+func WriteJSON(w io.Writer, findings []Finding) error {
+	if findings == nil {
+		findings = []Finding{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(findings)
+}
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level Severity `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     Severity        `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log for code scanning
+// tools. Rules have no line numbers, so results point at the rule file and
+// name the rule as a logical location.
+func WriteSARIF(w io.Writer, findings []Finding) error {
+	driver := sarifDriver{
+		Name:           "analyze-tags",
+		Version:        "1.0.0",
+		InformationURI: "https://github.com/mtnmunuklu/analyze-tags",
+	}
+
+	ruleIndexes := make(map[string]int)
+	for i, check := range Checks {
+		ruleIndexes[check.ID] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   check.ID,
+			ShortDescription:     sarifMessage{Text: check.Description},
+			DefaultConfiguration: sarifConfiguration{Level: check.Severity},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, finding := range findings {
+		results = append(results, sarifResult{
+			RuleID:    finding.Check,
+			RuleIndex: ruleIndexes[finding.Check],
+			Level:     finding.Severity,
+			Message:   sarifMessage{Text: finding.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(finding.Path)},
+				},
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               finding.Title,
+					FullyQualifiedName: finding.Location,
+					Kind:               "object",
+				}},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
@@ -7,25 +7,58 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"unicode"
 
 	"github.com/mtnmunuklu/analyze-tags/analytics"
-	"github.com/mtnmunuklu/analyze-tags/csiem"
-	"github.com/mtnmunuklu/analyze-tags/sigma"
+	"github.com/mtnmunuklu/analyze-tags/attack"
+	"github.com/mtnmunuklu/analyze-tags/lint"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/mtnmunuklu/analyze-tags/splunk"
+	"github.com/mtnmunuklu/analyze-tags/taxonomy"
 	"github.com/mtnmunuklu/analyze-tags/yara"
+	"github.com/mtnmunuklu/analyze-tags/yaral"
 )
 
 var (
-	filePath    string
-	fileContent string
-	showHelp    bool
-	outputPath  string
-	version     bool
-	useSigma    bool
-	useYara     bool
-	useCsiem    bool
-	outputChart bool
-	chartType   string
-	outputExcel bool
+	filePath     string
+	fileContent  string
+	showHelp     bool
+	outputPath   string
+	version      bool
+	useSigma     bool
+	useYara      bool
+	useCsiem     bool
+	useSuricata  bool
+	useElastic   bool
+	useSplunk    bool
+	useSentinel  bool
+	useWazuh     bool
+	useFalco     bool
+	useYaraL     bool
+	useNuclei    bool
+	useStix      bool
+	autoDetect   bool
+	outputChart  bool
+	chartType    string
+	outputExcel  bool
+	outputLayer  bool
+	layerColors  string
+	correlation  string
+	yaraMetaTags string
+	yaralTags    string
+	disabledIDS  bool
+	splunkTags   string
+	falcoResolve bool
+	groupBy      string
+	namespaces   string
+	chartField   string
+	attackPath   string
+	gapProfile   string
+	gapMinRules  int
+	taxonomyPath string
+	lintFormat   string
+	lintFailOn   string
+	diffBase     string
 )
 
 func init() {
@@ -36,10 +69,38 @@ func init() {
 	flag.BoolVar(&useSigma, "sigma", false, "Use Sigma rules")
 	flag.BoolVar(&useYara, "yara", false, "Use Yara rules")
 	flag.BoolVar(&useCsiem, "csiem", false, "Use Csiem rules")
+	flag.BoolVar(&useSuricata, "suricata", false, "Use Suricata/Snort rules")
+	flag.BoolVar(&useElastic, "elastic", false, "Use Elastic detection rules")
+	flag.BoolVar(&useSplunk, "splunk", false, "Use Splunk security content detections")
+	flag.BoolVar(&useSentinel, "sentinel", false, "Use Microsoft Sentinel analytics rules (YAML or ARM template JSON)")
+	flag.BoolVar(&useWazuh, "wazuh", false, "Use Wazuh/OSSEC XML rules")
+	flag.BoolVar(&useFalco, "falco", false, "Use Falco rules")
+	flag.BoolVar(&useYaraL, "yaral", false, "Use Chronicle YARA-L 2.0 rules")
+	flag.BoolVar(&useNuclei, "nuclei", false, "Use Nuclei templates")
+	flag.BoolVar(&useStix, "stix", false, "Use the indicators of STIX 2.1 bundles")
+	flag.BoolVar(&autoDetect, "auto", false, "Detect the rule format of each file automatically")
 	flag.BoolVar(&outputChart, "chart", false, "Generate chart")
-	flag.StringVar(&chartType, "chartType", "", "Specify one or more chart types to generate (comma-separated). Available chart types: bar, line, scatter, pie, boxplot, heatmap, radar, funnel, wordcloud, treemap, graph, tree")
+	flag.StringVar(&chartType, "chartType", "", "Specify one or more chart types to generate (comma-separated). Available chart types: bar, line, scatter, pie, boxplot, heatmap, radar, funnel, wordcloud, treemap, graph, tree, matrix")
 	flag.BoolVar(&outputExcel, "excel", false, "Generate excel")
+	flag.BoolVar(&outputLayer, "navigator", false, "Generate an ATT&CK Navigator layer")
+	flag.StringVar(&layerColors, "navigatorColors", strings.Join(analytics.DefaultNavigatorColors, ","), "Gradient colors of the Navigator layer from the lowest to the highest score (comma-separated hex colors)")
 	flag.StringVar(&outputPath, "output", ".", "Output directory")
+	flag.StringVar(&groupBy, "groupBy", "", "Break down the tag analysis by a rule field, e.g. format, severity, author, status, product, service, module or datasource")
+	flag.StringVar(&namespaces, "tagNamespaces", "", "Generate one set of charts per tag namespace with only the tags of that namespace, e.g. cwe,cve-year (comma-separated)")
+	flag.StringVar(&chartField, "chartField", "", "Chart the values of a rule field instead of the tags, e.g. severity, attack_tactic, attack_technique or attack_platform")
+	flag.StringVar(&attackPath, "attack", "", "Path of an ATT&CK STIX bundle (enterprise-attack.json) used to normalize ATT&CK tags and add technique names, tactics and platforms")
+	flag.StringVar(&taxonomyPath, "taxonomy", "", "Path of a YAML tag taxonomy with the namespaces, aliases, rewrites and case folding that bring the tags into canonical form")
+	flag.StringVar(&lintFormat, "lint", "", "Check the tags of every rule against the -attack bundle and the -taxonomy instead of analyzing them. Available formats: text (printed), json and sarif (written as lint.json or lint.sarif)")
+	flag.StringVar(&lintFailOn, "lintFailOn", string(lint.Error), "Lowest severity of the lint findings that makes the command exit with a non-zero code: error or warning")
+	flag.StringVar(&diffBase, "diff", "", "Compare the rules of the -filepath directory with an older ruleset: a directory, a git revision of -filepath, or a revision range such as main..HEAD or main...HEAD. Written as diff.xlsx with -excel and diff_chart.html with -chart")
+	flag.StringVar(&gapProfile, "gap", "", "Report the coverage gaps against a profile: a Navigator layer file, a group or software id resolved from the -attack bundle (e.g. G0049), or technique ids (comma-separated). Written as gap.xlsx with -excel and gap_chart.html with -chart")
+	flag.IntVar(&gapMinRules, "gapMinRules", analytics.DefaultGapMinRules, "Number of rules a technique of the gap profile needs to count as covered rather than weakly covered")
+	flag.StringVar(&yaraMetaTags, "yaraMetaTags", strings.Join(yara.DefaultMetaTagKeys, ","), "Yara meta keys whose values are added to the rule tags (comma-separated, empty to disable)")
+	flag.StringVar(&yaralTags, "yaralMetaTags", strings.Join(yaral.DefaultMetaTagKeys, ","), "YARA-L meta keys whose values are added to the rule tags as namespaced tags (comma-separated)")
+	flag.BoolVar(&disabledIDS, "suricataDisabled", false, "Include Suricata/Snort rules that are commented out")
+	flag.StringVar(&splunkTags, "splunkTags", strings.Join(splunk.DefaultTagCategories, ","), "Splunk tag categories flattened into namespaced tags (comma-separated)")
+	flag.BoolVar(&falcoResolve, "falcoResolve", true, "Apply Falco append and override items to the rules they modify across files")
+	flag.StringVar(&correlation, "correlation", "inherit", "How Sigma correlation rules contribute tags. Available modes: inherit, category")
 
 	flag.Parse()
 
@@ -59,19 +120,38 @@ func init() {
 		os.Exit(1)
 	}
 
-	if !useSigma && !useYara && !useCsiem {
-		fmt.Println("Please specify the type of rules using either the --sigma, --yara, or --csiem flag.")
+	if !useSigma && !useYara && !useCsiem && !useSuricata && !useElastic && !useSplunk && !useSentinel && !useWazuh && !useFalco && !useYaraL && !useNuclei && !useStix && !autoDetect {
+		fmt.Println("Please specify the type of rules using either the --sigma, --yara, --csiem, --suricata, --elastic, --splunk, --sentinel, --wazuh, --falco, --yaral, --nuclei, --stix, or --auto flag.")
 		printUsage()
 		os.Exit(1)
 	}
 
-	if !outputChart && !outputExcel {
-		fmt.Println("Please specify the output type using either the --chart or --excel flag.")
+	if !outputChart && !outputExcel && !outputLayer && lintFormat == "" {
+		fmt.Println("Please specify the output type using either the --chart, --excel, --navigator or --lint flag.")
 		printUsage()
 		os.Exit(1)
 	}
 
-	if outputChart && chartType == "" {
+	if lintFormat != "" {
+		if _, err := lint.FindFormat(lintFormat); err != nil {
+			fmt.Println("Error:", err)
+			printUsage()
+			os.Exit(1)
+		}
+		if lintFailOn != string(lint.Error) && lintFailOn != string(lint.Warning) {
+			fmt.Println("Please provide error or warning as the lint severity to fail on.")
+			printUsage()
+			os.Exit(1)
+		}
+	}
+
+	if diffBase != "" && filePath == "" {
+		fmt.Println("Please provide the directory to compare with -filepath.")
+		printUsage()
+		os.Exit(1)
+	}
+
+	if outputChart && chartType == "" && gapProfile == "" && diffBase == "" {
 		fmt.Println("Please provide the chart type.")
 		printUsage()
 		os.Exit(1)
@@ -80,14 +160,50 @@ func init() {
 }
 
 func printUsage() {
-	fmt.Println("Usage: analyze-tags -sigma/-yara/-csiem -filepath <path> [flags]")
+	fmt.Println("Usage: analyze-tags -sigma/-yara/-csiem/-suricata/-elastic/-splunk/-sentinel/-wazuh/-falco/-yaral/-nuclei/-stix/-auto -filepath <path> [flags]")
 	fmt.Println("Flags:")
 	flag.PrintDefaults()
 	fmt.Println("Example:")
-	fmt.Println("  analyze-tags -sigma/-yara/-csiem -filepath /path/to/file -chart -chartType \"wordcloud\"")
+	fmt.Println("  analyze-tags -sigma/-yara/-csiem/-suricata/-elastic/-splunk/-sentinel/-wazuh/-falco/-yaral/-nuclei/-stix/-auto -filepath /path/to/file -chart -chartType \"wordcloud\"")
+}
+
+func generateChart(rules []model.Rule, chartTypes []string, matrix *attack.Matrix) {
+	if chartField != "" {
+		rules = analytics.FieldTags(rules, chartField)
+	}
+
+	if namespaces == "" {
+		generateGroupCharts(rules, chartTypes, matrix, "", "")
+		return
+	}
+
+	for _, namespace := range strings.Split(namespaces, ",") {
+		namespaceRules := analytics.FilterTags(rules, []string{namespace})
+		if len(namespaceRules) == 0 {
+			fmt.Println("No tags found in namespace:", namespace)
+			continue
+		}
+		generateGroupCharts(namespaceRules, chartTypes, matrix, "_"+fileNamePart(namespace), namespace)
+	}
 }
 
-func generateChart(data map[string][]string, chartTypes []string) {
+func generateGroupCharts(rules []model.Rule, chartTypes []string, matrix *attack.Matrix, suffix string, subtitle string) {
+	if groupBy == "" {
+		generateCharts(rules, chartTypes, matrix, suffix, subtitle)
+		return
+	}
+
+	groups := analytics.GroupRules(rules, groupBy)
+	for group, groupRules := range groups {
+		title := fmt.Sprintf("%s: %s", groupBy, group)
+		if subtitle != "" {
+			title = fmt.Sprintf("%s, %s", subtitle, title)
+		}
+		generateCharts(groupRules, chartTypes, matrix, suffix+"_"+fileNamePart(group), title)
+	}
+}
+
+func generateCharts(rules []model.Rule, chartTypes []string, matrix *attack.Matrix, suffix string, subtitle string) {
 	for i, chartType := range chartTypes {
 		foundChartType, err := analytics.FindChartType(chartType)
 		if err != nil {
@@ -95,13 +211,17 @@ func generateChart(data map[string][]string, chartTypes []string) {
 			return
 		}
 
-		output := fmt.Sprintf("%s/%s%d_chart.html", outputPath, chartType, i)
+		output := fmt.Sprintf("%s/%s%d%s_chart.html", outputPath, chartType, i, suffix)
 		title := fmt.Sprintf("%s chart", chartType)
+		if subtitle != "" {
+			title = fmt.Sprintf("%s (%s)", title, subtitle)
+		}
 		params := analytics.ChartParams{
 			Type:   foundChartType,
-			Data:   data,
+			Data:   rules,
 			Title:  title,
 			Output: output,
+			Attack: matrix,
 		}
 
 		generator, err := analytics.GenerateChart(params)
@@ -119,12 +239,14 @@ func generateChart(data map[string][]string, chartTypes []string) {
 	}
 }
 
-func generateExcel(data map[string][]string) {
+func generateExcel(rules []model.Rule, matrix *attack.Matrix) {
 	output := fmt.Sprintf("%s/output.xlsx", outputPath)
 	params := analytics.ExcelParams{
 		SheetName: "Data",
-		Data:      data,
+		Data:      rules,
 		Output:    output,
+		GroupBy:   groupBy,
+		Attack:    matrix,
 	}
 
 	err := params.ToExcel()
@@ -134,14 +256,210 @@ func generateExcel(data map[string][]string) {
 	}
 }
 
+func generateLayer(rules []model.Rule, matrix *attack.Matrix) {
+	output := fmt.Sprintf("%s/layer.json", outputPath)
+	params := analytics.NavigatorParams{
+		Data:   rules,
+		Output: output,
+		Colors: strings.Split(layerColors, ","),
+		Attack: matrix,
+	}
+
+	err := params.ToNavigator()
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+}
+
+func generateGap(rules []model.Rule, matrix *attack.Matrix) {
+	profile, err := attack.LoadProfile(gapProfile, matrix)
+	if err != nil {
+		fmt.Println("Error loading gap profile:", err)
+		return
+	}
+
+	params := analytics.GapParams{
+		Profile:  profile,
+		Data:     rules,
+		MinRules: gapMinRules,
+		Attack:   matrix,
+	}
+
+	counts := make(map[analytics.CoverageStatus]int)
+	for _, technique := range params.Coverage() {
+		counts[technique.Status]++
+	}
+	fmt.Printf("Coverage of %s: %d uncovered, %d weakly covered and %d covered techniques\n",
+		profile.Name, counts[analytics.Uncovered], counts[analytics.WeaklyCovered], counts[analytics.Covered])
+
+	if outputChart {
+		if err := params.ToChart(fmt.Sprintf("%s/gap_chart.html", outputPath)); err != nil {
+			fmt.Println("Error generating chart: ", err)
+		}
+	}
+	if outputExcel {
+		if err := params.ToExcel(fmt.Sprintf("%s/gap.xlsx", outputPath)); err != nil {
+			fmt.Println("Error:", err)
+		}
+	}
+}
+
+// normalizeRules enriches the rules with the ATT&CK bundle and brings their
+// tags into the canonical form of the taxonomy, when they are given.
+func normalizeRules(rules []model.Rule, matrix *attack.Matrix, tax *taxonomy.Taxonomy) []model.Rule {
+	if matrix != nil {
+		for i := range rules {
+			rules[i] = matrix.Enrich(rules[i])
+		}
+	}
+
+	if tax != nil {
+		rules = tax.Apply(rules)
+	}
+
+	return rules
+}
+
+func generateDiff(matrix *attack.Matrix, tax *taxonomy.Taxonomy) {
+	oldFiles, newFiles, err := diffInputs(diffBase, filePath)
+	if err != nil {
+		fmt.Println("Error reading the rulesets to compare:", err)
+		os.Exit(1)
+	}
+
+	// A file that fails to parse would show its rules as removed or added,
+	// so the rulesets are only compared when both parse cleanly.
+	oldRules, oldErrors := parseRules(oldFiles)
+	newRules, newErrors := parseRules(newFiles)
+	if oldErrors > 0 || newErrors > 0 {
+		fmt.Printf("Not comparing the rulesets: %d parse errors in the old ruleset, %d in the new one\n", oldErrors, newErrors)
+		os.Exit(1)
+	}
+
+	params := analytics.DiffParams{
+		Old: normalizeRules(oldRules, matrix, tax),
+		New: normalizeRules(newRules, matrix, tax),
+	}
+
+	statusCounts := make(map[analytics.ChangeStatus]int)
+	for _, change := range params.RuleChanges() {
+		statusCounts[change.Status]++
+	}
+	fmt.Printf("%d rules added, %d removed and %d changed\n",
+		statusCounts[analytics.Added], statusCounts[analytics.Removed], statusCounts[analytics.Changed])
+
+	if outputChart {
+		if err := params.ToChart(fmt.Sprintf("%s/diff_chart.html", outputPath)); err != nil {
+			fmt.Println("Error generating chart: ", err)
+		}
+	}
+	if outputExcel {
+		if err := params.ToExcel(fmt.Sprintf("%s/diff.xlsx", outputPath)); err != nil {
+			fmt.Println("Error:", err)
+		}
+	}
+}
+
+// lintRules reports the lint findings of the rules and returns false when a
+// finding is at least as severe as -lintFailOn.
+func lintRules(rules []model.Rule, matrix *attack.Matrix, tax *taxonomy.Taxonomy) bool {
+	linter := lint.Linter{Attack: matrix, Taxonomy: tax}
+	findings := linter.Lint(rules)
+
+	format, _ := lint.FindFormat(lintFormat)
+	if format == lint.Text {
+		if err := lint.WriteText(os.Stdout, findings); err != nil {
+			fmt.Println("Error:", err)
+			return false
+		}
+	} else {
+		output := fmt.Sprintf("%s/lint.%s", outputPath, format.Extension())
+		file, err := os.Create(output)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return false
+		}
+		defer file.Close()
+
+		if err := lint.Write(file, format, findings); err != nil {
+			fmt.Println("Error:", err)
+			return false
+		}
+		fmt.Printf("%d errors, %d warnings written to %s\n", lint.Count(findings, lint.Error), lint.Count(findings, lint.Warning), output)
+	}
+
+	failing := lint.Count(findings, lint.Error)
+	if lintFailOn == string(lint.Warning) {
+		failing += lint.Count(findings, lint.Warning)
+	}
+	return failing == 0
+}
+
+// stopOnError is called before main returns after an error. In lint mode it
+// exits with a non-zero code so that a CI job fails when the rules could not
+// be linted.
+func stopOnError() {
+	if lintFormat != "" {
+		os.Exit(1)
+	}
+}
+
+func printDuplicateTitles(rules []model.Rule) {
+	for _, duplicate := range model.DuplicateTitles(rules) {
+		fmt.Printf("Duplicate title %q is used by %d rules:\n", duplicate.Title, len(duplicate.Rules))
+		for _, rule := range duplicate.Rules {
+			fmt.Printf("  %s (id: %s)\n", rule.Location(), rule.Key())
+		}
+	}
+}
+
+func fileNamePart(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
 func main() {
 
+	var matrix *attack.Matrix
+	if attackPath != "" {
+		var err error
+		matrix, err = attack.Load(attackPath)
+		if err != nil {
+			fmt.Println("Error loading ATT&CK bundle:", err)
+			stopOnError()
+			return
+		}
+	}
+
+	var tax *taxonomy.Taxonomy
+	if taxonomyPath != "" {
+		var err error
+		tax, err = taxonomy.Load(taxonomyPath)
+		if err != nil {
+			fmt.Println("Error loading taxonomy:", err)
+			stopOnError()
+			return
+		}
+	}
+
+	if diffBase != "" {
+		generateDiff(matrix, tax)
+		return
+	}
+
 	fileContents := make(map[string][]byte)
+	readErrors := 0
 
 	if filePath != "" {
 		fileInfo, err := os.Stat(filePath)
 		if err != nil {
 			fmt.Println("Error getting file/directory info:", err)
+			stopOnError()
 			return
 		}
 
@@ -149,6 +467,7 @@ func main() {
 			filepath.Walk(filePath, func(path string, info os.FileInfo, err error) error {
 				if err != nil {
 					fmt.Println("Error accessing file:", err)
+					readErrors++
 					return nil
 				}
 
@@ -156,6 +475,7 @@ func main() {
 					content, err := os.ReadFile(path)
 					if err != nil {
 						fmt.Println("Error reading file:", err)
+						readErrors++
 						return nil
 					}
 					fileContents[path] = content
@@ -166,6 +486,7 @@ func main() {
 			fileContents[filePath], err = os.ReadFile(filePath)
 			if err != nil {
 				fmt.Println("Error reading file:", err)
+				stopOnError()
 				return
 			}
 		}
@@ -178,6 +499,7 @@ func main() {
 				decodedContent, err := base64.StdEncoding.DecodeString(line)
 				if err != nil {
 					fmt.Println("Error decoding base64 content:", err)
+					stopOnError()
 					return
 				}
 				fileContents[line] = decodedContent
@@ -186,50 +508,45 @@ func main() {
 			decodedContent, err := base64.StdEncoding.DecodeString(fileContent)
 			if err != nil {
 				fmt.Println("Error decoding base64 content:", err)
+				stopOnError()
 				return
 			}
 			fileContents["filecontent"] = decodedContent
 		}
 	}
 
-	data := make(map[string][]string)
+	rules, parseErrors := parseRules(fileContents)
+	printDuplicateTitles(rules)
 
-	for _, fileContent := range fileContents {
-		if useSigma {
-			sigmaRule, err := sigma.ParseRule(fileContent)
-			if err != nil {
-				fmt.Println("Error parsing rule:", err)
-				continue
-			}
-
-			data[sigmaRule.Title] = sigmaRule.Tags
-
-		} else if useYara {
-			yaraRuleSet, err := yara.ParseByte(fileContent)
-			if err != nil {
-				fmt.Println("Error parsing rule:", err)
-				continue
-			}
-
-			for _, yaraRule := range yaraRuleSet.Rules {
-				data[yaraRule.Identifier] = yaraRule.Tags
-			}
-		} else if useCsiem {
-			csiemRule, err := csiem.ParseRule(fileContent)
-			if err != nil {
-				fmt.Println("Error parsing rule:", err)
-				continue
-			}
+	// Tags are linted as the rules declare them, before they are normalized.
+	// Files that could not be read or parsed fail the lint as well.
+	if lintFormat != "" {
+		passed := lintRules(rules, matrix, tax)
+		if readErrors > 0 || parseErrors > 0 {
+			fmt.Printf("%d read errors, %d parse errors\n", readErrors, parseErrors)
+			passed = false
+		}
+		if !passed {
+			os.Exit(1)
+		}
+		return
+	}
 
-			data[csiemRule.Name] = csiemRule.Tags
+	rules = normalizeRules(rules, matrix, tax)
 
-		}
+	if gapProfile != "" {
+		generateGap(rules, matrix)
+		return
 	}
 
 	if outputChart {
 		chartTypes := strings.Split(chartType, ",")
-		generateChart(data, chartTypes)
-	} else if outputExcel {
-		generateExcel(data)
+		generateChart(rules, chartTypes, matrix)
+	}
+	if outputExcel {
+		generateExcel(rules, matrix)
+	}
+	if outputLayer {
+		generateLayer(rules, matrix)
 	}
 }
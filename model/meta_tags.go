@@ -0,0 +1,58 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MetaTagOptions controls how MetaTags turns meta values into tags. Formats
+// whose meta values are lists of single-word ids, such as YARA, split them
+// on whitespace, while formats whose values are names, such as the ATT&CK
+// tactics of YARA-L, keep the words of a value together.
+type MetaTagOptions struct {
+	// Namespaced prefixes every tag with its meta key, e.g. severity:Low.
+	// The values of the tags key are never prefixed.
+	Namespaced bool
+
+	// KeepSpaces splits values on commas, semicolons and pipes only.
+	KeepSpaces bool
+}
+
+// MetaTags returns the values of the given meta keys split into individual
+// tags, without duplicates. Meta keys are expected in lower case and keys
+// are matched case-insensitively.
+func MetaTags(meta map[string][]string, keys []string, options MetaTagOptions) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		for _, value := range meta[key] {
+			for _, item := range SplitTags(value, options.KeepSpaces) {
+				tag := item
+				if options.Namespaced && key != "tags" {
+					tag = key + ":" + item
+				}
+				if !seen[tag] {
+					tags = append(tags, tag)
+					seen[tag] = true
+				}
+			}
+		}
+	}
+	return tags
+}
+
+// SplitTags splits a meta value on commas, semicolons, pipes and, unless
+// keepSpaces is set, whitespace. Surrounding whitespace and empty items are
+// dropped.
+func SplitTags(value string, keepSpaces bool) []string {
+	var tags []string
+	for _, item := range strings.FieldsFunc(value, func(c rune) bool {
+		return c == ',' || c == ';' || c == '|' || (!keepSpaces && unicode.IsSpace(c))
+	}) {
+		if item = strings.TrimSpace(item); item != "" {
+			tags = append(tags, item)
+		}
+	}
+	return tags
+}
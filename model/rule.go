@@ -0,0 +1,135 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Format string
+
+const (
+	Unknown  Format = ""
+	Sigma    Format = "sigma"
+	Yara     Format = "yara"
+	Csiem    Format = "csiem"
+	Suricata Format = "suricata"
+	Elastic  Format = "elastic"
+	Splunk   Format = "splunk"
+	Sentinel Format = "sentinel"
+	Wazuh    Format = "wazuh"
+	Falco    Format = "falco"
+	YaraL    Format = "yaral"
+	Nuclei   Format = "nuclei"
+	Stix     Format = "stix"
+)
+
+// Rule is the normalized form of a rule produced by the adapters of every
+// parser package and consumed by the analytics package. Index is the
+// position of the rule within the file at Path.
+type Rule struct {
+	Format   Format
+	Path     string
+	Index    int
+	ID       string
+	Title    string
+	Tags     []string
+	Severity string
+	Author   string
+	Date     string
+	Modified string
+
+	// Metadata holds format specific fields such as the Sigma log source or
+	// the modules imported by a YARA rule, keyed by lowercase field name.
+	Metadata map[string][]string
+}
+
+// Key returns a stable identity for the rule: its declared id when the
+// format has one, otherwise its location.
+func (r Rule) Key() string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return r.Location()
+}
+
+// Location returns the source path and position of the rule within the file,
+// which is unique within a single run even when rules share an id.
+func (r Rule) Location() string {
+	return fmt.Sprintf("%s#%d", r.Path, r.Index)
+}
+
+// Field returns the values of a named field so rules can be grouped by any
+// of the common fields or by a metadata key.
+func (r Rule) Field(name string) []string {
+	var value string
+	switch strings.ToLower(name) {
+	case "format":
+		value = string(r.Format)
+	case "path":
+		value = r.Path
+	case "id":
+		value = r.Key()
+	case "title":
+		value = r.Title
+	case "severity":
+		value = r.Severity
+	case "author":
+		value = r.Author
+	case "date":
+		value = r.Date
+	case "modified":
+		value = r.Modified
+	case "tag", "tags":
+		return r.Tags
+	default:
+		return r.Metadata[strings.ToLower(name)]
+	}
+
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+func (r *Rule) AddMetadata(key string, values ...string) {
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		if r.Metadata == nil {
+			r.Metadata = make(map[string][]string)
+		}
+		r.Metadata[key] = append(r.Metadata[key], value)
+	}
+}
+
+type Duplicate struct {
+	Title string
+	Rules []Rule
+}
+
+// DuplicateTitles returns the titles shared by more than one rule, sorted by
+// title, together with the colliding rules. Rules without a title are not
+// duplicates of each other.
+func DuplicateTitles(rules []Rule) []Duplicate {
+	byTitle := make(map[string][]Rule)
+	for _, rule := range rules {
+		if rule.Title == "" {
+			continue
+		}
+		byTitle[rule.Title] = append(byTitle[rule.Title], rule)
+	}
+
+	var duplicates []Duplicate
+	for title, titled := range byTitle {
+		if len(titled) > 1 {
+			duplicates = append(duplicates, Duplicate{Title: title, Rules: titled})
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].Title < duplicates[j].Title
+	})
+	return duplicates
+}
@@ -0,0 +1,60 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "abc", model.Rule{ID: "abc", Path: "a.yml", Index: 1}.Key())
+	assert.Equal(t, "a.yml#1", model.Rule{Path: "a.yml", Index: 1}.Key())
+	assert.Equal(t, "a.yml#1", model.Rule{ID: "abc", Path: "a.yml", Index: 1}.Location())
+}
+
+func TestDuplicateTitles(t *testing.T) {
+	rules := []model.Rule{
+		{Title: "Foo", Path: "a.yml"},
+		{Title: "Bar", Path: "b.yml"},
+		{Title: "Foo", Path: "c.yml"},
+		{Title: "Baz", Path: "d.yml"},
+		{Title: "Baz", Path: "d.yml", Index: 1},
+		{Path: "e.rules"},
+		{Path: "e.rules", Index: 1},
+	}
+
+	duplicates := model.DuplicateTitles(rules)
+	if assert.Len(t, duplicates, 2) {
+		assert.Equal(t, "Baz", duplicates[0].Title)
+		assert.Equal(t, "d.yml#1", duplicates[0].Rules[1].Location())
+		assert.Equal(t, "Foo", duplicates[1].Title)
+		assert.Equal(t, []string{"a.yml", "c.yml"}, []string{duplicates[1].Rules[0].Path, duplicates[1].Rules[1].Path})
+	}
+}
+
+func TestField(t *testing.T) {
+	rule := model.Rule{Format: model.Sigma, Severity: "high", Tags: []string{"a", "b"}}
+	rule.AddMetadata("product", "windows", "")
+
+	assert.Equal(t, []string{"sigma"}, rule.Field("format"))
+	assert.Equal(t, []string{"high"}, rule.Field("Severity"))
+	assert.Equal(t, []string{"a", "b"}, rule.Field("tags"))
+	assert.Equal(t, []string{"windows"}, rule.Field("product"))
+	assert.Nil(t, rule.Field("author"))
+}
+
+func TestMetaTags(t *testing.T) {
+	meta := map[string][]string{
+		"tags":                {"attack.t1110, attack.credential_access"},
+		"mitre_attack":        {"T1055 T1027.005|T1055"},
+		"mitre_attack_tactic": {"Credential Access; Defense Evasion"},
+	}
+
+	assert.Equal(t,
+		[]string{"attack.t1110", "attack.credential_access", "T1055", "T1027.005"},
+		model.MetaTags(meta, []string{"TAGS", "mitre_attack"}, model.MetaTagOptions{}))
+	assert.Equal(t,
+		[]string{"attack.t1110", "attack.credential_access", "mitre_attack_tactic:Credential Access", "mitre_attack_tactic:Defense Evasion"},
+		model.MetaTags(meta, []string{"tags", "mitre_attack_tactic"}, model.MetaTagOptions{Namespaced: true, KeepSpaces: true}))
+}
@@ -0,0 +1,61 @@
+package nuclei
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+var cveYearPattern = regexp.MustCompile(`^CVE-(\d{4})-\d+$`)
+
+// Tags returns the template tags followed by namespaced tags for the
+// severity, the CVE and CWE ids and the year of every CVE, e.g.
+// severity:critical, cve:CVE-2021-44228, cwe:CWE-502 and cve-year:2021.
+func (t Template) Tags() []string {
+	var tags []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		if !seen[tag] {
+			tags = append(tags, tag)
+			seen[tag] = true
+		}
+	}
+
+	for _, tag := range t.Info.Tags {
+		add(strings.ToLower(tag))
+	}
+	if t.Info.Severity != "" {
+		add("severity:" + strings.ToLower(t.Info.Severity))
+	}
+	for _, cve := range t.Info.Classification.CVEID {
+		cve = strings.ToUpper(cve)
+		add("cve:" + cve)
+		if match := cveYearPattern.FindStringSubmatch(cve); match != nil {
+			add("cve-year:" + match[1])
+		}
+	}
+	for _, cwe := range t.Info.Classification.CWEID {
+		add("cwe:" + strings.ToUpper(cwe))
+	}
+
+	return tags
+}
+
+func (t Template) Normalize(path string, index int) model.Rule {
+	rule := model.Rule{
+		Format:   model.Nuclei,
+		Path:     path,
+		Index:    index,
+		ID:       t.ID,
+		Title:    t.Info.Name,
+		Tags:     t.Tags(),
+		Severity: strings.ToLower(t.Info.Severity),
+		Author:   strings.Join(t.Info.Author, ", "),
+	}
+
+	rule.AddMetadata("protocol", t.Protocols...)
+	rule.AddMetadata("reference", t.Info.Reference...)
+
+	return rule
+}
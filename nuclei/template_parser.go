@@ -0,0 +1,110 @@
+package nuclei
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template is a Nuclei template. Only the id and info block are decoded,
+// together with the names of the protocols the template sends requests
+// with.
+type Template struct {
+	ID        string   `yaml:"id"`
+	Info      Info     `yaml:"info"`
+	Protocols []string `yaml:"-" json:",omitempty"`
+}
+
+type Info struct {
+	Name           string                 `yaml:"name"`
+	Author         StringSlice            `yaml:"author"`
+	Severity       string                 `yaml:"severity"`
+	Description    string                 `yaml:"description" json:",omitempty"`
+	Reference      StringSlice            `yaml:"reference" json:",omitempty"`
+	Tags           StringSlice            `yaml:"tags" json:",omitempty"`
+	Classification Classification         `yaml:"classification" json:",omitempty"`
+	Metadata       map[string]interface{} `yaml:"metadata" json:",omitempty"`
+}
+
+type Classification struct {
+	CVEID       StringSlice `yaml:"cve-id" json:",omitempty"`
+	CWEID       StringSlice `yaml:"cwe-id" json:",omitempty"`
+	CVSSMetrics string      `yaml:"cvss-metrics" json:",omitempty"`
+	CVSSScore   float64     `yaml:"cvss-score" json:",omitempty"`
+}
+
+// StringSlice is a field written either as a YAML list or as a comma
+// separated string, as Nuclei accepts both for authors, tags and
+// classification ids.
+type StringSlice []string
+
+func (s *StringSlice) UnmarshalYAML(value *yaml.Node) error {
+	var items []string
+	if value.Kind == yaml.SequenceNode {
+		if err := value.Decode(&items); err != nil {
+			return err
+		}
+	} else {
+		var text string
+		if err := value.Decode(&text); err != nil {
+			return err
+		}
+		items = strings.Split(text, ",")
+	}
+
+	*s = nil
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			*s = append(*s, item)
+		}
+	}
+	return nil
+}
+
+// protocolKeys are the top level keys that hold the requests of a template,
+// including the legacy requests key for HTTP.
+var protocolKeys = []string{
+	"http", "requests", "dns", "file", "network", "tcp", "headless", "ssl",
+	"websocket", "whois", "code", "javascript", "workflows",
+}
+
+func ParseTemplate(input []byte) (Template, error) {
+
+	template := Template{}
+
+	err := yaml.Unmarshal(input, &template)
+	if err != nil {
+		return template, err
+	}
+
+	var keys map[string]yaml.Node
+	if err := yaml.Unmarshal(input, &keys); err != nil {
+		return template, err
+	}
+
+	for _, key := range protocolKeys {
+		if _, ok := keys[key]; !ok {
+			continue
+		}
+		switch key {
+		case "requests":
+			key = "http"
+		case "tcp":
+			key = "network"
+		}
+		if !contains(template.Protocols, key) {
+			template.Protocols = append(template.Protocols, key)
+		}
+	}
+
+	return template, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package nuclei_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/mtnmunuklu/analyze-tags/nuclei"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTemplate(t *testing.T) {
+	contents, err := os.ReadFile("./data/templates/CVE-2021-44228.yaml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	template, err := nuclei.ParseTemplate(contents)
+	if err != nil {
+		t.Fatalf("error parsing template: %v", err)
+	}
+
+	assert.Equal(t, "CVE-2021-44228", template.ID)
+	assert.Equal(t, "critical", template.Info.Severity)
+	assert.Len(t, template.Info.Author, 7)
+	assert.Equal(t, nuclei.StringSlice{"CVE-2021-44228"}, template.Info.Classification.CVEID)
+	assert.Equal(t, 10.0, template.Info.Classification.CVSSScore)
+	assert.Equal(t, []string{"http"}, template.Protocols)
+	assert.Equal(t, []string{
+		"cve", "cve2021", "rce", "oast", "log4j", "injection", "kev",
+		"severity:critical",
+		"cve:CVE-2021-44228",
+		"cve-year:2021",
+		"cwe:CWE-77", "cwe:CWE-502", "cwe:CWE-400", "cwe:CWE-20",
+	}, template.Tags())
+}
+
+func TestNormalize(t *testing.T) {
+	contents, err := os.ReadFile("./data/templates/git-config.yaml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	template, err := nuclei.ParseTemplate(contents)
+	if err != nil {
+		t.Fatalf("error parsing template: %v", err)
+	}
+
+	normalized := template.Normalize("git-config.yaml", 0)
+	assert.Equal(t, model.Nuclei, normalized.Format)
+	assert.Equal(t, "git-config", normalized.Key())
+	assert.Equal(t, "Git Configuration - Detect", normalized.Title)
+	assert.Equal(t, "Ice3man, DhiyaneshDK", normalized.Author)
+	assert.Equal(t, []string{"config", "git", "exposure", "severity:medium", "cwe:CWE-200"}, normalized.Tags)
+	assert.Equal(t, []string{"http"}, normalized.Field("protocol"))
+}
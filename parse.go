@@ -0,0 +1,296 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/csiem"
+	"github.com/mtnmunuklu/analyze-tags/detect"
+	"github.com/mtnmunuklu/analyze-tags/elastic"
+	"github.com/mtnmunuklu/analyze-tags/falco"
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/mtnmunuklu/analyze-tags/nuclei"
+	"github.com/mtnmunuklu/analyze-tags/sentinel"
+	"github.com/mtnmunuklu/analyze-tags/sigma"
+	"github.com/mtnmunuklu/analyze-tags/splunk"
+	"github.com/mtnmunuklu/analyze-tags/stix"
+	"github.com/mtnmunuklu/analyze-tags/suricata"
+	"github.com/mtnmunuklu/analyze-tags/wazuh"
+	"github.com/mtnmunuklu/analyze-tags/yara"
+	"github.com/mtnmunuklu/analyze-tags/yaral"
+)
+
+func selectedFormat() model.Format {
+	switch {
+	case useSigma:
+		return model.Sigma
+	case useYara:
+		return model.Yara
+	case useCsiem:
+		return model.Csiem
+	case useSuricata:
+		return model.Suricata
+	case useElastic:
+		return model.Elastic
+	case useSplunk:
+		return model.Splunk
+	case useSentinel:
+		return model.Sentinel
+	case useWazuh:
+		return model.Wazuh
+	case useFalco:
+		return model.Falco
+	case useYaraL:
+		return model.YaraL
+	case useNuclei:
+		return model.Nuclei
+	case useStix:
+		return model.Stix
+	default:
+		return model.Unknown
+	}
+}
+
+// parseRules parses the rules of every file and returns them together with
+// the number of files and rules that could not be parsed or resolved.
+func parseRules(fileContents map[string][]byte) ([]model.Rule, int) {
+	paths := make([]string, 0, len(fileContents))
+	for path := range fileContents {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var rules []model.Rule
+	var sigmaRules []sigma.Rule
+	var sigmaPaths []string
+	var sigmaIndexes []int
+	falcoRuleset := falco.NewRuleset()
+	formatCounts := make(map[model.Format]int)
+
+	errorCount := 0
+	parseError := func(path string, err error) {
+		fmt.Printf("Error parsing rule %s: %v\n", path, err)
+		errorCount++
+	}
+
+	for _, path := range paths {
+		fileContent := fileContents[path]
+
+		format := selectedFormat()
+		if autoDetect {
+			format = detect.Detect(path, fileContent)
+			if format == model.Unknown {
+				fmt.Println("Skipping file with unknown rule format:", path)
+				continue
+			}
+		}
+
+		switch format {
+		case model.Sigma:
+			parsed, err := sigma.ParseRules(fileContent)
+			if err != nil {
+				parseError(path, err)
+				continue
+			}
+
+			for i := range parsed {
+				sigmaPaths = append(sigmaPaths, path)
+				sigmaIndexes = append(sigmaIndexes, i)
+			}
+			sigmaRules = append(sigmaRules, parsed...)
+			formatCounts[format] += len(parsed)
+
+		case model.Yara:
+			yaraRules, err := yara.ParseRules(fileContent)
+			if err != nil {
+				parseError(path, err)
+				continue
+			}
+
+			for i, yaraRule := range yaraRules {
+				if yaraMetaTags != "" {
+					yaraRule.MergeMetaTags(strings.Split(yaraMetaTags, ","))
+				}
+				rules = append(rules, yaraRule.Normalize(path, i))
+			}
+			formatCounts[format] += len(yaraRules)
+
+		case model.Csiem:
+			csiemRules, err := csiem.ParseRules(fileContent)
+			if err != nil {
+				parseError(path, err)
+				continue
+			}
+
+			for i, csiemRule := range csiemRules {
+				rules = append(rules, csiemRule.Normalize(path, i))
+			}
+			formatCounts[format] += len(csiemRules)
+
+		case model.Suricata:
+			suricataRules, err := suricata.ParseRules(fileContent)
+			if err != nil {
+				parseError(path, err)
+			}
+
+			for i, suricataRule := range suricataRules {
+				if !suricataRule.Enabled && !disabledIDS {
+					continue
+				}
+				rules = append(rules, suricataRule.Normalize(path, i))
+				formatCounts[format]++
+			}
+
+		case model.Elastic:
+			elasticRule, err := elastic.ParseRule(fileContent)
+			if err != nil {
+				parseError(path, err)
+				continue
+			}
+
+			rules = append(rules, elasticRule.Normalize(path, 0))
+			formatCounts[format]++
+
+		case model.Splunk:
+			splunkRule, err := splunk.ParseRule(fileContent)
+			if err != nil {
+				parseError(path, err)
+				continue
+			}
+
+			rules = append(rules, splunkRule.Normalize(path, 0, strings.Split(splunkTags, ",")))
+			formatCounts[format]++
+
+		case model.Sentinel:
+			sentinelRules, err := sentinel.ParseRules(fileContent)
+			if err != nil {
+				parseError(path, err)
+				continue
+			}
+
+			for i, sentinelRule := range sentinelRules {
+				rules = append(rules, sentinelRule.Normalize(path, i))
+			}
+			formatCounts[format] += len(sentinelRules)
+
+		case model.Wazuh:
+			wazuhRules, err := wazuh.ParseRules(fileContent)
+			if err != nil {
+				parseError(path, err)
+				continue
+			}
+
+			for i, wazuhRule := range wazuhRules {
+				rules = append(rules, wazuhRule.Normalize(path, i))
+			}
+			formatCounts[format] += len(wazuhRules)
+
+		case model.Falco:
+			if falcoResolve {
+				if err := falcoRuleset.Add(path, fileContent); err != nil {
+					parseError(path, err)
+				}
+				continue
+			}
+
+			falcoRules, err := falco.ParseRules(fileContent)
+			if err != nil {
+				parseError(path, err)
+				continue
+			}
+
+			for i, falcoRule := range falcoRules {
+				if falcoRule.Modifies() {
+					continue
+				}
+				rules = append(rules, falcoRule.Normalize(path, i))
+				formatCounts[format]++
+			}
+
+		case model.YaraL:
+			yaralRules, err := yaral.ParseRules(fileContent)
+			if err != nil {
+				parseError(path, err)
+				continue
+			}
+
+			for i, yaralRule := range yaralRules {
+				rules = append(rules, yaralRule.Normalize(path, i, strings.Split(yaralTags, ",")))
+			}
+			formatCounts[format] += len(yaralRules)
+
+		case model.Nuclei:
+			template, err := nuclei.ParseTemplate(fileContent)
+			if err != nil {
+				parseError(path, err)
+				continue
+			}
+
+			rules = append(rules, template.Normalize(path, 0))
+			formatCounts[format]++
+
+		case model.Stix:
+			indicators, err := stix.ParseIndicators(fileContent)
+			if err != nil {
+				parseError(path, err)
+				continue
+			}
+
+			for i, indicator := range indicators {
+				rules = append(rules, indicator.Normalize(path, i))
+			}
+			formatCounts[format] += len(indicators)
+		}
+	}
+
+	if falcoResolve {
+		falcoRules, err := falcoRuleset.Normalize()
+		if err != nil {
+			fmt.Println("Error resolving Falco rules:", err)
+			errorCount++
+		}
+
+		rules = append(rules, falcoRules...)
+		if len(falcoRules) > 0 {
+			formatCounts[model.Falco] += len(falcoRules)
+		}
+	}
+
+	if autoDetect {
+		printFormatCounts(formatCounts)
+	}
+
+	if len(sigmaRules) > 0 {
+		correlationMode, err := sigma.FindCorrelationMode(correlation)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return rules, errorCount + 1
+		}
+
+		sigmaRules, err = sigma.ResolveCorrelations(sigmaRules, correlationMode)
+		if err != nil {
+			fmt.Println("Error resolving correlation rules:", err)
+			errorCount++
+		}
+
+		for i, sigmaRule := range sigmaRules {
+			rules = append(rules, sigmaRule.Normalize(sigmaPaths[i], sigmaIndexes[i]))
+		}
+	}
+
+	return rules, errorCount
+}
+
+func printFormatCounts(formatCounts map[model.Format]int) {
+	formats := make([]string, 0, len(formatCounts))
+	for format := range formatCounts {
+		formats = append(formats, string(format))
+	}
+	sort.Strings(formats)
+
+	fmt.Println("Parsed rules per format:")
+	for _, format := range formats {
+		fmt.Printf("  %s: %d\n", format, formatCounts[model.Format(format)])
+	}
+}
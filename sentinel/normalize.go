@@ -0,0 +1,71 @@
+package sentinel
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+// AttackTags maps the tactics and techniques of the rule into the Sigma tag
+// vocabulary, e.g. CredentialAccess becomes attack.credential_access and
+// T1110 becomes attack.t1110.
+func (r Rule) AttackTags() []string {
+	var tags []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		if !seen[tag] {
+			tags = append(tags, tag)
+			seen[tag] = true
+		}
+	}
+
+	for _, tactic := range r.Tactics {
+		add("attack." + snakeCase(tactic))
+	}
+	for _, technique := range r.RelevantTechniques {
+		add("attack." + strings.ToLower(strings.TrimSpace(technique)))
+	}
+
+	return tags
+}
+
+func (r Rule) Normalize(path string, index int) model.Rule {
+	rule := model.Rule{
+		Format:   model.Sentinel,
+		Path:     path,
+		Index:    index,
+		ID:       r.ID,
+		Title:    r.Name,
+		Tags:     r.AttackTags(),
+		Severity: strings.ToLower(r.Severity),
+		Author:   r.Metadata.Author.Name,
+	}
+
+	rule.AddMetadata("kind", r.Kind)
+	rule.AddMetadata("version", r.Version)
+	for _, connector := range r.RequiredDataConnectors {
+		rule.AddMetadata("datasource", connector.ConnectorID)
+		rule.AddMetadata("datatype", connector.DataTypes...)
+	}
+
+	return rule
+}
+
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, c := range strings.TrimSpace(s) {
+		switch {
+		case c == ' ' || c == '-':
+			b.WriteRune('_')
+		case unicode.IsUpper(c):
+			if i > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(c))
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
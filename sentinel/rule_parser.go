@@ -0,0 +1,112 @@
+package sentinel
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule is a Microsoft Sentinel analytics rule, either written in the
+// Azure-Sentinel YAML layout or exported as an ARM template.
+type Rule struct {
+	Name                   string
+	ID                     string          `yaml:",omitempty" json:",omitempty"`
+	Description            string          `yaml:",omitempty" json:",omitempty"`
+	Severity               string          `yaml:",omitempty" json:",omitempty"`
+	Kind                   string          `yaml:",omitempty" json:",omitempty"`
+	Enabled                *bool           `yaml:",omitempty" json:",omitempty"`
+	Query                  string          `yaml:",omitempty" json:",omitempty"`
+	Version                string          `yaml:",omitempty" json:",omitempty"`
+	RequiredDataConnectors []DataConnector `yaml:"requiredDataConnectors,omitempty" json:",omitempty"`
+	Tactics                []string        `yaml:",omitempty" json:",omitempty"`
+	RelevantTechniques     []string        `yaml:"relevantTechniques,omitempty" json:",omitempty"`
+	Metadata               RuleMetadata    `yaml:",omitempty" json:",omitempty"`
+}
+
+type DataConnector struct {
+	ConnectorID string   `yaml:"connectorId,omitempty" json:",omitempty"`
+	DataTypes   []string `yaml:"dataTypes,omitempty" json:",omitempty"`
+}
+
+type RuleMetadata struct {
+	Author struct {
+		Name string
+	}
+}
+
+type armTemplate struct {
+	Resources []armResource `json:"resources"`
+}
+
+type armResource struct {
+	Type       string        `json:"type"`
+	Name       string        `json:"name"`
+	Kind       string        `json:"kind"`
+	Properties armProperties `json:"properties"`
+}
+
+type armProperties struct {
+	DisplayName     string   `json:"displayName"`
+	Description     string   `json:"description"`
+	Severity        string   `json:"severity"`
+	Enabled         *bool    `json:"enabled"`
+	Query           string   `json:"query"`
+	Tactics         []string `json:"tactics"`
+	Techniques      []string `json:"techniques"`
+	SubTechniques   []string `json:"subTechniques"`
+	TemplateVersion string   `json:"templateVersion"`
+}
+
+var guidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+func ParseRule(input []byte) (Rule, error) {
+
+	rule := Rule{}
+
+	err := yaml.Unmarshal(input, &rule)
+
+	return rule, err
+}
+
+// ParseRules parses a YAML analytics rule or an ARM template holding one or
+// more alert rule resources.
+func ParseRules(input []byte) ([]Rule, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(input), []byte("{")) {
+		rule, err := ParseRule(input)
+		if err != nil {
+			return nil, err
+		}
+		return []Rule{rule}, nil
+	}
+
+	template := armTemplate{}
+	if err := json.Unmarshal(input, &template); err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	for _, resource := range template.Resources {
+		if !strings.HasSuffix(strings.ToLower(resource.Type), "/alertrules") {
+			continue
+		}
+
+		properties := resource.Properties
+		rules = append(rules, Rule{
+			ID:                 guidPattern.FindString(resource.Name),
+			Name:               properties.DisplayName,
+			Description:        properties.Description,
+			Severity:           properties.Severity,
+			Kind:               resource.Kind,
+			Enabled:            properties.Enabled,
+			Query:              properties.Query,
+			Version:            properties.TemplateVersion,
+			Tactics:            properties.Tactics,
+			RelevantTechniques: append(append([]string(nil), properties.Techniques...), properties.SubTechniques...),
+		})
+	}
+
+	return rules, nil
+}
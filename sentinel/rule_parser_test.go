@@ -0,0 +1,78 @@
+package sentinel_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/mtnmunuklu/analyze-tags/sentinel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRulesYAML(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/SigninBruteForce-AzurePortal.yaml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := sentinel.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	if !assert.Len(t, rules, 1) {
+		return
+	}
+
+	rule := rules[0]
+	assert.Equal(t, "28b42356-45af-40a6-a0b4-a554cdfd5d8a", rule.ID)
+	assert.Equal(t, "Medium", rule.Severity)
+	assert.Equal(t, "Scheduled", rule.Kind)
+	assert.Equal(t, "2.0.4", rule.Version)
+	assert.Equal(t, "Microsoft Security Research", rule.Metadata.Author.Name)
+	assert.Equal(t, []sentinel.DataConnector{{ConnectorID: "AzureActiveDirectory", DataTypes: []string{"SigninLogs", "AADNonInteractiveUserSignInLogs"}}}, rule.RequiredDataConnectors)
+	assert.Equal(t, []string{"attack.credential_access", "attack.initial_access", "attack.t1110", "attack.t1078.004"}, rule.AttackTags())
+}
+
+func TestParseRulesARM(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/arm_export.json")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := sentinel.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	if !assert.Len(t, rules, 2) {
+		return
+	}
+
+	assert.Equal(t, "28b42356-45af-40a6-a0b4-a554cdfd5d8a", rules[0].ID)
+	assert.Equal(t, "Brute force attack against Azure Portal", rules[0].Name)
+	assert.Equal(t, []string{"attack.credential_access", "attack.initial_access", "attack.t1110", "attack.t1078", "attack.t1078.004"}, rules[0].AttackTags())
+
+	assert.Equal(t, "NRT", rules[1].Kind)
+	assert.False(t, *rules[1].Enabled)
+	assert.Equal(t, []string{"attack.persistence", "attack.privilege_escalation", "attack.command_and_control", "attack.t1543"}, rules[1].AttackTags())
+}
+
+func TestNormalize(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/SigninBruteForce-AzurePortal.yaml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := sentinel.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	normalized := rules[0].Normalize("rule.yaml", 0)
+	assert.Equal(t, model.Sentinel, normalized.Format)
+	assert.Equal(t, "medium", normalized.Severity)
+	assert.Equal(t, []string{"attack.credential_access", "attack.initial_access", "attack.t1110", "attack.t1078.004"}, normalized.Tags)
+	assert.Equal(t, []string{"AzureActiveDirectory"}, normalized.Field("datasource"))
+	assert.Equal(t, []string{"SigninLogs", "AADNonInteractiveUserSignInLogs"}, normalized.Field("datatype"))
+}
@@ -0,0 +1,133 @@
+package sigma
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	actionGlobal = "global"
+	actionReset  = "reset"
+	actionRepeat = "repeat"
+)
+
+// ParseRules parses every document of a Sigma rule collection. Documents
+// marked with "action: global" are merged into all following rules until an
+// "action: reset" document is seen, and "action: repeat" documents are merged
+// on top of the previous rule.
+func ParseRules(input []byte) ([]Rule, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(input))
+
+	var rules []Rule
+	var global, previous *yaml.Node
+
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rules, err
+		}
+
+		if len(document.Content) == 0 {
+			continue
+		}
+
+		node := document.Content[0]
+		if node.Kind != yaml.MappingNode {
+			return rules, fmt.Errorf("line %d: rule document is not a mapping", node.Line)
+		}
+
+		action := removeKey(node, "action")
+		switch action {
+		case actionGlobal:
+			global = mergeNodes(global, node)
+			continue
+		case actionReset:
+			global = nil
+			continue
+		case actionRepeat:
+			if previous == nil {
+				return rules, fmt.Errorf("line %d: repeat action without a preceding rule", node.Line)
+			}
+			node = mergeNodes(previous, node)
+		case "":
+			node = mergeNodes(global, node)
+		default:
+			return rules, fmt.Errorf("line %d: unknown collection action %q", node.Line, action)
+		}
+
+		rule := Rule{}
+		if err := node.Decode(&rule); err != nil {
+			return rules, err
+		}
+
+		rules = append(rules, rule)
+		previous = node
+	}
+
+	return rules, nil
+}
+
+func removeKey(node *yaml.Node, key string) string {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			value := node.Content[i+1].Value
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return value
+		}
+	}
+	return ""
+}
+
+// mergeNodes returns a copy of base with overlay merged into it. Nested
+// mappings are merged recursively, any other value in overlay replaces the
+// one in base.
+func mergeNodes(base, overlay *yaml.Node) *yaml.Node {
+	if base == nil {
+		return copyNode(overlay)
+	}
+
+	merged := copyNode(base)
+	if merged.Kind != yaml.MappingNode || overlay.Kind != yaml.MappingNode {
+		return copyNode(overlay)
+	}
+
+	for i := 0; i+1 < len(overlay.Content); i += 2 {
+		key, value := overlay.Content[i], overlay.Content[i+1]
+
+		found := false
+		for j := 0; j+1 < len(merged.Content); j += 2 {
+			if merged.Content[j].Value == key.Value {
+				merged.Content[j+1] = mergeNodes(merged.Content[j+1], value)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			merged.Content = append(merged.Content, copyNode(key), copyNode(value))
+		}
+	}
+
+	return merged
+}
+
+func copyNode(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+
+	copied := *node
+	copied.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		copied.Content[i] = copyNode(child)
+	}
+
+	return &copied
+}
@@ -0,0 +1,147 @@
+package sigma
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type CorrelationType string
+
+const (
+	EventCount      CorrelationType = "event_count"
+	ValueCount      CorrelationType = "value_count"
+	Temporal        CorrelationType = "temporal"
+	TemporalOrdered CorrelationType = "temporal_ordered"
+)
+
+type Correlation struct {
+	Type      CorrelationType
+	Rules     []string                     `yaml:",omitempty" json:",omitempty"`
+	Aliases   map[string]map[string]string `yaml:",omitempty" json:",omitempty"`
+	GroupBy   []string                     `yaml:"group-by,omitempty" json:",omitempty"`
+	Timespan  string                       `yaml:",omitempty" json:",omitempty"`
+	Condition map[string]interface{}       `yaml:",omitempty" json:",omitempty"`
+	Generate  bool                         `yaml:",omitempty" json:",omitempty"`
+}
+
+// CorrelationMode controls which tags a correlation rule contributes to the
+// tag analysis.
+type CorrelationMode string
+
+const (
+	// CorrelationInherit adds the tags of every referenced rule to the
+	// correlation rule.
+	CorrelationInherit CorrelationMode = "inherit"
+	// CorrelationCategory adds a "correlation.<type>" tag so correlation
+	// rules are reported as their own category.
+	CorrelationCategory CorrelationMode = "category"
+)
+
+func FindCorrelationMode(mode string) (CorrelationMode, error) {
+	switch mode {
+	case "inherit":
+		return CorrelationInherit, nil
+	case "category":
+		return CorrelationCategory, nil
+	default:
+		return "", fmt.Errorf("unsupported correlation mode: %s", mode)
+	}
+}
+
+func (r Rule) IsCorrelation() bool {
+	return r.Correlation != nil
+}
+
+// ResolveCorrelations returns a copy of rules in which the tags of every
+// correlation rule have been resolved according to mode. References are
+// looked up by rule id and name within rules. Rules are always returned; the
+// error reports references that could not be resolved.
+func ResolveCorrelations(rules []Rule, mode CorrelationMode) ([]Rule, error) {
+	byReference := make(map[string]int)
+	for i, rule := range rules {
+		if rule.ID != "" {
+			byReference[rule.ID] = i
+		}
+		if rule.Name != "" {
+			byReference[rule.Name] = i
+		}
+	}
+
+	var errs []error
+	resolved := make([]Rule, len(rules))
+	for i, rule := range rules {
+		resolved[i] = rule
+		if !rule.IsCorrelation() {
+			continue
+		}
+
+		switch mode {
+		case CorrelationCategory:
+			resolved[i].Tags = appendUnique(rule.Tags, "correlation."+string(rule.Correlation.Type))
+		case CorrelationInherit:
+			tags, err := inheritTags(rules, byReference, i, map[int]bool{})
+			if err != nil {
+				errs = append(errs, err)
+			}
+			resolved[i].Tags = tags
+		}
+	}
+
+	return resolved, joinErrors(errs)
+}
+
+func inheritTags(rules []Rule, byReference map[string]int, index int, visiting map[int]bool) ([]string, error) {
+	rule := rules[index]
+	tags := append([]string(nil), rule.Tags...)
+	if !rule.IsCorrelation() || visiting[index] {
+		return tags, nil
+	}
+	visiting[index] = true
+	defer delete(visiting, index)
+
+	var errs []error
+	for _, reference := range rule.Correlation.Rules {
+		referenced, ok := byReference[reference]
+		if !ok {
+			errs = append(errs, fmt.Errorf("correlation rule %q references unknown rule %q", rule.Title, reference))
+			continue
+		}
+
+		inherited, err := inheritTags(rules, byReference, referenced, visiting)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		tags = appendUnique(tags, inherited...)
+	}
+
+	return tags, joinErrors(errs)
+}
+
+func appendUnique(tags []string, values ...string) []string {
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		seen[tag] = true
+	}
+
+	result := append([]string(nil), tags...)
+	for _, value := range values {
+		if !seen[value] {
+			result = append(result, value)
+			seen[value] = true
+		}
+	}
+	return result
+}
+
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return errors.New(strings.Join(messages, "; "))
+}
@@ -0,0 +1,30 @@
+package sigma
+
+import "github.com/mtnmunuklu/analyze-tags/model"
+
+func (r Rule) Normalize(path string, index int) model.Rule {
+	rule := model.Rule{
+		Format:   model.Sigma,
+		Path:     path,
+		Index:    index,
+		ID:       r.ID,
+		Title:    r.Title,
+		Tags:     r.Tags,
+		Severity: r.Level,
+		Author:   r.Author,
+		Date:     r.Date,
+		Modified: r.Modified,
+	}
+
+	rule.AddMetadata("name", r.Name)
+	rule.AddMetadata("status", r.Status)
+	rule.AddMetadata("category", r.Logsource.Category)
+	rule.AddMetadata("product", r.Logsource.Product)
+	rule.AddMetadata("service", r.Logsource.Service)
+	rule.AddMetadata("reference", r.References...)
+	if r.IsCorrelation() {
+		rule.AddMetadata("correlation", string(r.Correlation.Type))
+	}
+
+	return rule
+}
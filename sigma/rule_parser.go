@@ -5,9 +5,78 @@ import (
 )
 
 type Rule struct {
-	Title string
+	Title       string
+	ID          string        `yaml:",omitempty" json:",omitempty"`
+	Name        string        `yaml:",omitempty" json:",omitempty"`
+	Related     []RelatedRule `yaml:",omitempty" json:",omitempty"`
+	Status      string        `yaml:",omitempty" json:",omitempty"`
+	Description string        `yaml:",omitempty" json:",omitempty"`
+	References  []string      `yaml:",omitempty" json:",omitempty"`
+	Author      string        `yaml:",omitempty" json:",omitempty"`
+	Date        string        `yaml:",omitempty" json:",omitempty"`
+	Modified    string        `yaml:",omitempty" json:",omitempty"`
 
 	Tags []string `yaml:",omitempty" json:",omitempty"`
+
+	Logsource      Logsource `yaml:",omitempty" json:",omitempty"`
+	Detection      Detection `yaml:",omitempty" json:",omitempty"`
+	Fields         []string  `yaml:",omitempty" json:",omitempty"`
+	FalsePositives []string  `yaml:"falsepositives,omitempty" json:",omitempty"`
+	Level          string    `yaml:",omitempty" json:",omitempty"`
+
+	Correlation *Correlation `yaml:",omitempty" json:",omitempty"`
+}
+
+type RelatedRule struct {
+	ID   string
+	Type string
+}
+
+type Logsource struct {
+	Category   string `yaml:",omitempty" json:",omitempty"`
+	Product    string `yaml:",omitempty" json:",omitempty"`
+	Service    string `yaml:",omitempty" json:",omitempty"`
+	Definition string `yaml:",omitempty" json:",omitempty"`
+}
+
+// Detection holds the named search identifiers of a rule together with its
+// condition, which Sigma allows to be either a single string or a list.
+type Detection map[string]interface{}
+
+func (d *Detection) UnmarshalYAML(node *yaml.Node) error {
+	var detection map[string]interface{}
+	if err := node.Decode(&detection); err != nil {
+		return err
+	}
+	*d = detection
+	return nil
+}
+
+func (d Detection) Conditions() []string {
+	switch condition := d["condition"].(type) {
+	case string:
+		return []string{condition}
+	case []interface{}:
+		var conditions []string
+		for _, c := range condition {
+			if s, ok := c.(string); ok {
+				conditions = append(conditions, s)
+			}
+		}
+		return conditions
+	}
+	return nil
+}
+
+func (d Detection) Searches() map[string]interface{} {
+	searches := make(map[string]interface{})
+	for name, search := range d {
+		if name == "condition" || name == "timeframe" {
+			continue
+		}
+		searches[name] = search
+	}
+	return searches
 }
 
 func ParseRule(input []byte) (Rule, error) {
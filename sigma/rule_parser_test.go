@@ -6,7 +6,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/mtnmunuklu/analyze-tags/model"
 	"github.com/mtnmunuklu/analyze-tags/sigma"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestParseRule(t *testing.T) {
@@ -33,3 +35,165 @@ func TestParseRule(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestParseRuleFields(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/zeek_smb_converted_win_susp_psexec.yml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rule, err := sigma.ParseRule(contents)
+	if err != nil {
+		t.Fatalf("error parsing rule: %v", err)
+	}
+
+	assert.Equal(t, "f1b3a22a-45e6-4004-afb5-4291f9c21166", rule.ID)
+	assert.Equal(t, "test", rule.Status)
+	assert.Equal(t, "high", rule.Level)
+	assert.Equal(t, "Samir Bousseaden, @neu5ron, Tim Shelton", rule.Author)
+	assert.Equal(t, "2020/04/02", rule.Date)
+	assert.Equal(t, "2022/12/27", rule.Modified)
+	assert.Equal(t, []sigma.RelatedRule{{ID: "c462f537-a1e3-41a6-b5fc-b2c2cef9bf82", Type: "derived"}}, rule.Related)
+	assert.Equal(t, sigma.Logsource{Product: "zeek", Service: "smb_files"}, rule.Logsource)
+	assert.Equal(t, []string{"Unknown"}, rule.FalsePositives)
+	assert.Equal(t, []string{"selection and not filter"}, rule.Detection.Conditions())
+	assert.Len(t, rule.Detection.Searches(), 2)
+}
+
+func TestParseRuleISODate(t *testing.T) {
+	rule, err := sigma.ParseRule([]byte(`
+title: Dated
+date: 2023-01-02
+modified: 2024-03-04
+detection:
+  selection:
+    foo: bar
+  condition:
+    - selection
+    - not selection
+`))
+	if err != nil {
+		t.Fatalf("error parsing rule: %v", err)
+	}
+
+	assert.Equal(t, "2023-01-02", rule.Date)
+	assert.Equal(t, "2024-03-04", rule.Modified)
+	assert.Equal(t, []string{"selection", "not selection"}, rule.Detection.Conditions())
+}
+
+func TestParseRulesCollection(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/win_susp_sdelete_collection.yml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := sigma.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	if assert.Len(t, rules, 3) {
+		assert.Equal(t, "Secure Deletion with SDelete", rules[0].Title)
+		assert.Equal(t, sigma.Logsource{Product: "windows", Service: "security"}, rules[0].Logsource)
+		assert.Len(t, rules[0].Tags, 6)
+
+		assert.Equal(t, "Secure Deletion with SDelete", rules[1].Title)
+		assert.Equal(t, sigma.Logsource{Product: "windows", Service: "sysmon"}, rules[1].Logsource)
+		assert.Equal(t, "medium", rules[1].Level)
+		assert.Equal(t, 23, rules[1].Detection["selection"].(map[string]interface{})["EventID"])
+		assert.Equal(t, []string{"selection"}, rules[1].Detection.Conditions())
+
+		assert.Equal(t, "SDelete Use", rules[2].Title)
+		assert.Empty(t, rules[2].Tags)
+		assert.Equal(t, "low", rules[2].Level)
+	}
+}
+
+func TestParseRulesSingle(t *testing.T) {
+	rules, err := sigma.ParseRules([]byte("title: Single\ntags:\n  - attack.t1059\n"))
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	assert.Equal(t, []sigma.Rule{{Title: "Single", Tags: []string{"attack.t1059"}}}, rules)
+}
+
+func TestParseRulesRepeatWithoutRule(t *testing.T) {
+	_, err := sigma.ParseRules([]byte("action: repeat\ntitle: Orphan\n"))
+	assert.EqualError(t, err, "line 1: repeat action without a preceding rule")
+}
+
+func TestResolveCorrelations(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/win_security_brute_force_correlation.yml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := sigma.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	if !assert.Len(t, rules, 4) {
+		return
+	}
+	assert.False(t, rules[0].IsCorrelation())
+	assert.True(t, rules[2].IsCorrelation())
+	assert.Equal(t, sigma.EventCount, rules[2].Correlation.Type)
+	assert.Equal(t, []string{"IpAddress"}, rules[2].Correlation.GroupBy)
+	assert.Equal(t, "10m", rules[2].Correlation.Timespan)
+	assert.Equal(t, 10, rules[2].Correlation.Condition["gte"])
+
+	inherited, err := sigma.ResolveCorrelations(rules, sigma.CorrelationInherit)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"attack.credential_access", "attack.t1110"}, inherited[2].Tags)
+	assert.Equal(t, []string{"attack.t1110.001", "attack.credential_access", "attack.t1110", "attack.initial_access", "attack.t1078"}, inherited[3].Tags)
+	assert.Equal(t, rules[0].Tags, inherited[0].Tags)
+
+	categorized, err := sigma.ResolveCorrelations(rules, sigma.CorrelationCategory)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"correlation.event_count"}, categorized[2].Tags)
+	assert.Equal(t, []string{"attack.t1110.001", "correlation.temporal_ordered"}, categorized[3].Tags)
+}
+
+func TestResolveCorrelationsUnknownReference(t *testing.T) {
+	rules, err := sigma.ParseRules([]byte(`
+title: Orphan Correlation
+correlation:
+  type: value_count
+  rules:
+    - missing_rule
+  timespan: 1h
+  condition:
+    field: User
+    gte: 5
+`))
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	resolved, err := sigma.ResolveCorrelations(rules, sigma.CorrelationInherit)
+	assert.EqualError(t, err, `correlation rule "Orphan Correlation" references unknown rule "missing_rule"`)
+	assert.Len(t, resolved, 1)
+}
+
+func TestNormalize(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/zeek_smb_converted_win_susp_psexec.yml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rule, err := sigma.ParseRule(contents)
+	if err != nil {
+		t.Fatalf("error parsing rule: %v", err)
+	}
+
+	normalized := rule.Normalize("zeek.yml", 0)
+	assert.Equal(t, model.Sigma, normalized.Format)
+	assert.Equal(t, "zeek.yml", normalized.Path)
+	assert.Equal(t, rule.ID, normalized.ID)
+	assert.Equal(t, "high", normalized.Severity)
+	assert.Equal(t, []string{"zeek"}, normalized.Field("product"))
+	assert.Equal(t, []string{"smb_files"}, normalized.Field("service"))
+	assert.Equal(t, []string{"test"}, normalized.Field("status"))
+}
@@ -0,0 +1,28 @@
+package splunk
+
+import "github.com/mtnmunuklu/analyze-tags/model"
+
+func (r Rule) Normalize(path string, index int, categories []string) model.Rule {
+	rule := model.Rule{
+		Format: model.Splunk,
+		Path:   path,
+		Index:  index,
+		ID:     r.ID,
+		Title:  r.Name,
+		Tags:   r.FlattenTags(categories),
+		Author: r.Author,
+		Date:   r.Date,
+	}
+
+	if severity := r.TagValues("severity"); len(severity) > 0 {
+		rule.Severity = severity[0]
+	}
+
+	rule.AddMetadata("status", r.Status)
+	rule.AddMetadata("type", r.Type)
+	rule.AddMetadata("datasource", r.DataSource...)
+	rule.AddMetadata("product", r.TagValues("product")...)
+	rule.AddMetadata("risk_score", r.TagValues("risk_score")...)
+
+	return rule
+}
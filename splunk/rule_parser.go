@@ -0,0 +1,101 @@
+package splunk
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule is a detection from Splunk's security_content repository. Tags is the
+// nested tags object of the detection, keyed by tag category.
+type Rule struct {
+	Name        string
+	ID          string   `yaml:",omitempty" json:",omitempty"`
+	Version     int      `yaml:",omitempty" json:",omitempty"`
+	Date        string   `yaml:",omitempty" json:",omitempty"`
+	Author      string   `yaml:",omitempty" json:",omitempty"`
+	Status      string   `yaml:",omitempty" json:",omitempty"`
+	Type        string   `yaml:",omitempty" json:",omitempty"`
+	Description string   `yaml:",omitempty" json:",omitempty"`
+	DataSource  []string `yaml:"data_source,omitempty" json:",omitempty"`
+	Search      string   `yaml:",omitempty" json:",omitempty"`
+	References  []string `yaml:",omitempty" json:",omitempty"`
+
+	Tags map[string]interface{} `yaml:",omitempty" json:",omitempty"`
+}
+
+// DefaultTagCategories lists the categories of the nested tags object that
+// are flattened into namespaced tags.
+var DefaultTagCategories = []string{
+	"analytic_story",
+	"mitre_attack_id",
+	"kill_chain_phases",
+	"asset_type",
+	"security_domain",
+	"cis20",
+	"nist",
+}
+
+func ParseRule(input []byte) (Rule, error) {
+
+	rule := Rule{}
+
+	err := yaml.Unmarshal(input, &rule)
+
+	return rule, err
+}
+
+// FlattenTags returns the values of the given tag categories as namespaced
+// tags such as "analytic_story:Ransomware". Scalar values and lists of
+// scalars are supported, nested objects are skipped.
+func (r Rule) FlattenTags(categories []string) []string {
+	var tags []string
+	for _, category := range categories {
+		for _, value := range r.TagValues(category) {
+			tags = append(tags, category+":"+value)
+		}
+	}
+	return tags
+}
+
+// TagValues returns the scalar values of a single tag category.
+func (r Rule) TagValues(category string) []string {
+	var values []string
+	switch value := r.Tags[category].(type) {
+	case nil:
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := scalar(item); ok {
+				values = append(values, s)
+			}
+		}
+	default:
+		if s, ok := scalar(value); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// TagCategories returns the categories of the nested tags object in sorted
+// order.
+func (r Rule) TagCategories() []string {
+	categories := make([]string, 0, len(r.Tags))
+	for category := range r.Tags {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+func scalar(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, value != ""
+	case int, int64, float64, bool:
+		return fmt.Sprint(value), true
+	default:
+		return "", false
+	}
+}
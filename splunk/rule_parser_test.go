@@ -0,0 +1,62 @@
+package splunk_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/mtnmunuklu/analyze-tags/splunk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlattenTags(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/powershell_4104_hunting.yml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rule, err := splunk.ParseRule(contents)
+	if err != nil {
+		t.Fatalf("error parsing rule: %v", err)
+	}
+
+	assert.Equal(t, "d6f2b006-0041-11ec-8885-acde48001122", rule.ID)
+	assert.Equal(t, "2024-05-16", rule.Date)
+	assert.Equal(t, []string{"Powershell Script Block Logging 4104"}, rule.DataSource)
+	assert.Equal(t, []string{
+		"analytic_story:Malicious PowerShell",
+		"analytic_story:Hermetic Wiper",
+		"analytic_story:Ransomware",
+		"mitre_attack_id:T1059",
+		"mitre_attack_id:T1059.001",
+		"kill_chain_phases:Installation",
+		"asset_type:Endpoint",
+		"security_domain:endpoint",
+	}, rule.FlattenTags(splunk.DefaultTagCategories))
+	assert.Equal(t, []string{"confidence:80"}, rule.FlattenTags([]string{"confidence", "observable"}))
+	assert.Contains(t, rule.TagCategories(), "required_fields")
+}
+
+func TestNormalize(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/powershell_4104_hunting.yml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rule, err := splunk.ParseRule(contents)
+	if err != nil {
+		t.Fatalf("error parsing rule: %v", err)
+	}
+
+	normalized := rule.Normalize("hunting.yml", 0, []string{"analytic_story"})
+	assert.Equal(t, model.Splunk, normalized.Format)
+	assert.Equal(t, "PowerShell 4104 Hunting", normalized.Title)
+	assert.Equal(t, []string{
+		"analytic_story:Malicious PowerShell",
+		"analytic_story:Hermetic Wiper",
+		"analytic_story:Ransomware",
+	}, normalized.Tags)
+	assert.Equal(t, []string{"Hunting"}, normalized.Field("type"))
+	assert.Equal(t, []string{"64"}, normalized.Field("risk_score"))
+	assert.Len(t, normalized.Field("product"), 3)
+}
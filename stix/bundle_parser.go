@@ -0,0 +1,122 @@
+package stix
+
+import (
+	"encoding/json"
+)
+
+// Indicator is a STIX 2.1 indicator together with the attack patterns it
+// indicates and the name of the identity that created it.
+type Indicator struct {
+	ID              string           `json:"id"`
+	Name            string           `json:"name"`
+	Description     string           `json:"description"`
+	Pattern         string           `json:"pattern"`
+	PatternType     string           `json:"pattern_type"`
+	IndicatorTypes  []string         `json:"indicator_types"`
+	Labels          []string         `json:"labels"`
+	KillChainPhases []KillChainPhase `json:"kill_chain_phases"`
+	Created         string           `json:"created"`
+	Modified        string           `json:"modified"`
+	CreatedByRef    string           `json:"created_by_ref"`
+	Revoked         bool             `json:"revoked"`
+
+	CreatedBy      string          `json:"-"`
+	AttackPatterns []AttackPattern `json:"-"`
+}
+
+type AttackPattern struct {
+	ID                 string              `json:"id"`
+	Name               string              `json:"name"`
+	ExternalReferences []ExternalReference `json:"external_references"`
+	KillChainPhases    []KillChainPhase    `json:"kill_chain_phases"`
+}
+
+type KillChainPhase struct {
+	KillChainName string `json:"kill_chain_name"`
+	PhaseName     string `json:"phase_name"`
+}
+
+type ExternalReference struct {
+	SourceName string `json:"source_name"`
+	ExternalID string `json:"external_id"`
+	URL        string `json:"url"`
+}
+
+type object struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	RelationshipType string `json:"relationship_type"`
+	SourceRef        string `json:"source_ref"`
+	TargetRef        string `json:"target_ref"`
+	Revoked          bool   `json:"revoked"`
+}
+
+type bundle struct {
+	Objects []json.RawMessage `json:"objects"`
+}
+
+// ParseIndicators returns the indicators of a STIX bundle in order. Revoked
+// indicators are skipped, and the attack patterns an indicator indicates are
+// resolved from the "indicates" relationships with the indicator as source.
+// Other relationship types are ignored.
+func ParseIndicators(input []byte) ([]Indicator, error) {
+	b := bundle{}
+	if err := json.Unmarshal(input, &b); err != nil {
+		return nil, err
+	}
+
+	var indicators []Indicator
+	attackPatterns := make(map[string]AttackPattern)
+	identities := make(map[string]string)
+	related := make(map[string][]string)
+
+	for _, raw := range b.Objects {
+		o := object{}
+		if err := json.Unmarshal(raw, &o); err != nil {
+			return nil, err
+		}
+
+		switch o.Type {
+		case "indicator":
+			indicator := Indicator{}
+			if err := json.Unmarshal(raw, &indicator); err != nil {
+				return nil, err
+			}
+			if !indicator.Revoked {
+				indicators = append(indicators, indicator)
+			}
+		case "attack-pattern":
+			attackPattern := AttackPattern{}
+			if err := json.Unmarshal(raw, &attackPattern); err != nil {
+				return nil, err
+			}
+			if !o.Revoked {
+				attackPatterns[attackPattern.ID] = attackPattern
+			}
+		case "identity":
+			identities[o.ID] = o.Name
+		case "relationship":
+			if o.RelationshipType == "indicates" {
+				related[o.SourceRef] = append(related[o.SourceRef], o.TargetRef)
+			}
+		}
+	}
+
+	for i := range indicators {
+		indicator := &indicators[i]
+		indicator.CreatedBy = identities[indicator.CreatedByRef]
+
+		seen := make(map[string]bool)
+		for _, ref := range related[indicator.ID] {
+			attackPattern, ok := attackPatterns[ref]
+			if ok && !seen[ref] {
+				indicator.AttackPatterns = append(indicator.AttackPatterns, attackPattern)
+				seen[ref] = true
+			}
+		}
+	}
+
+	return indicators, nil
+}
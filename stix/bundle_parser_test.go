@@ -0,0 +1,82 @@
+package stix_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/mtnmunuklu/analyze-tags/stix"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIndicators(t *testing.T) {
+	contents, err := os.ReadFile("./data/bundles/indicators.json")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	indicators, err := stix.ParseIndicators(contents)
+	if err != nil {
+		t.Fatalf("error parsing bundle: %v", err)
+	}
+
+	if !assert.Len(t, indicators, 2) {
+		return
+	}
+
+	indicator := indicators[0]
+	assert.Equal(t, "File hash for Poison Ivy variant", indicator.Name)
+	assert.Equal(t, "Example Threat Intel", indicator.CreatedBy)
+	assert.Empty(t, indicator.AttackPatterns)
+	assert.Equal(t, []string{"poison-ivy", "rat", "lockheed-martin-cyber-kill-chain:installation", "attack.command_and_control"}, indicator.Tags())
+
+	indicator = indicators[1]
+	assert.Len(t, indicator.AttackPatterns, 2)
+	assert.Equal(t, []string{"attack.execution", "attack.t1059.001", "attack.t1059", "capec:CAPEC-248"}, indicator.Tags())
+}
+
+func TestParseIndicatorsRelationships(t *testing.T) {
+	indicators, err := stix.ParseIndicators([]byte(`{
+  "type": "bundle",
+  "objects": [
+    {"type": "indicator", "id": "indicator--1", "name": "Indicator"},
+    {"type": "attack-pattern", "id": "attack-pattern--1", "name": "PowerShell",
+     "external_references": [{"source_name": "mitre-attack", "external_id": "T1059.001"}]},
+    {"type": "attack-pattern", "id": "attack-pattern--2", "name": "Phishing",
+     "external_references": [{"source_name": "mitre-attack", "external_id": "T1566"}]},
+    {"type": "attack-pattern", "id": "attack-pattern--3", "name": "Ingress Tool Transfer",
+     "external_references": [{"source_name": "mitre-attack", "external_id": "T1105"}]},
+    {"type": "relationship", "relationship_type": "related-to", "source_ref": "indicator--1", "target_ref": "attack-pattern--1"},
+    {"type": "relationship", "relationship_type": "derived-from", "source_ref": "indicator--1", "target_ref": "attack-pattern--2"},
+    {"type": "relationship", "relationship_type": "indicates", "source_ref": "attack-pattern--3", "target_ref": "indicator--1"}
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("error parsing bundle: %v", err)
+	}
+
+	if assert.Len(t, indicators, 1) {
+		assert.Empty(t, indicators[0].AttackPatterns)
+		assert.Empty(t, indicators[0].Tags())
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	contents, err := os.ReadFile("./data/bundles/indicators.json")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	indicators, err := stix.ParseIndicators(contents)
+	if err != nil {
+		t.Fatalf("error parsing bundle: %v", err)
+	}
+
+	normalized := indicators[1].Normalize("indicators.json", 1)
+	assert.Equal(t, model.Stix, normalized.Format)
+	assert.Equal(t, "indicator--8e2e2d2b-17d4-4cbf-938f-98ee46b3cd3f", normalized.Key())
+	assert.Equal(t, "[process:command_line MATCHES '-enc(odedcommand)? [A-Za-z0-9+/=]{40,}']", normalized.Title)
+	assert.Equal(t, "2016-04-06T20:03:48.000Z", normalized.Date)
+	assert.Equal(t, []string{"stix"}, normalized.Field("pattern_type"))
+	assert.Equal(t, []string{"malicious-activity"}, normalized.Field("indicator_type"))
+}
@@ -0,0 +1,78 @@
+package stix
+
+import (
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+const mitreAttack = "mitre-attack"
+
+// Tags returns the labels of the indicator, its kill chain phases and the
+// ATT&CK ids of the related attack patterns. Phases of the mitre-attack
+// kill chain and ATT&CK ids use the Sigma tag vocabulary, e.g.
+// attack.credential_access and attack.t1110.001, phases of other kill
+// chains are namespaced by the kill chain name.
+func (i Indicator) Tags() []string {
+	var tags []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		if tag != "" && !seen[tag] {
+			tags = append(tags, tag)
+			seen[tag] = true
+		}
+	}
+
+	for _, label := range i.Labels {
+		add(label)
+	}
+	for _, phase := range i.KillChainPhases {
+		add(phase.tag())
+	}
+	for _, attackPattern := range i.AttackPatterns {
+		for _, reference := range attackPattern.ExternalReferences {
+			switch reference.SourceName {
+			case mitreAttack:
+				add("attack." + strings.ToLower(reference.ExternalID))
+			case "capec":
+				add("capec:" + reference.ExternalID)
+			}
+		}
+	}
+
+	return tags
+}
+
+func (p KillChainPhase) tag() string {
+	if p.PhaseName == "" {
+		return ""
+	}
+	if p.KillChainName == mitreAttack {
+		return "attack." + strings.ReplaceAll(p.PhaseName, "-", "_")
+	}
+	return p.KillChainName + ":" + p.PhaseName
+}
+
+func (i Indicator) Normalize(path string, index int) model.Rule {
+	title := i.Name
+	if title == "" {
+		title = i.Pattern
+	}
+
+	rule := model.Rule{
+		Format:   model.Stix,
+		Path:     path,
+		Index:    index,
+		ID:       i.ID,
+		Title:    title,
+		Tags:     i.Tags(),
+		Author:   i.CreatedBy,
+		Date:     i.Created,
+		Modified: i.Modified,
+	}
+
+	rule.AddMetadata("pattern_type", i.PatternType)
+	rule.AddMetadata("indicator_type", i.IndicatorTypes...)
+
+	return rule
+}
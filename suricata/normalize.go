@@ -0,0 +1,94 @@
+package suricata
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+// Tags returns the tags carried by the rule: its classtype, the MITRE
+// ATT&CK metadata entries, free-form "tag" metadata and CVE references.
+func (r Rule) Tags() []string {
+	var tags []string
+	if r.Classtype != "" {
+		tags = append(tags, "classtype:"+r.Classtype)
+	}
+
+	keys := make([]string, 0, len(r.Metadata))
+	for key := range r.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch {
+		case strings.HasPrefix(key, "mitre_"):
+			for _, value := range r.Metadata[key] {
+				tags = append(tags, key+":"+value)
+			}
+		case key == "tag":
+			tags = append(tags, r.Metadata[key]...)
+		}
+	}
+
+	for _, reference := range r.References {
+		if strings.EqualFold(reference.Type, "cve") {
+			tags = append(tags, "cve:"+normalizeCVE(reference.Value))
+		}
+	}
+
+	return tags
+}
+
+// ID returns the sid of the rule, prefixed with its gid when the rule is
+// not from the default generator 1, since generators reuse each other's sids.
+func (r Rule) ID() string {
+	if r.GID != "" && r.GID != "1" && r.SID != "" {
+		return r.GID + ":" + r.SID
+	}
+	return r.SID
+}
+
+func (r Rule) Normalize(path string, index int) model.Rule {
+	rule := model.Rule{
+		Format:   model.Suricata,
+		Path:     path,
+		Index:    index,
+		ID:       r.ID(),
+		Title:    r.Msg,
+		Tags:     r.Tags(),
+		Severity: r.Priority,
+		Date:     metadataDate(r.Metadata["created_at"]),
+		Modified: metadataDate(r.Metadata["updated_at"]),
+	}
+
+	if severity := r.Metadata["signature_severity"]; len(severity) > 0 {
+		rule.Severity = severity[0]
+	}
+
+	for key, values := range r.Metadata {
+		rule.AddMetadata(key, values...)
+	}
+	rule.AddMetadata("action", r.Action)
+	rule.AddMetadata("protocol", r.Protocol)
+	rule.AddMetadata("enabled", strconv.FormatBool(r.Enabled))
+
+	return rule
+}
+
+func normalizeCVE(value string) string {
+	value = strings.ToUpper(value)
+	if !strings.HasPrefix(value, "CVE-") {
+		value = "CVE-" + value
+	}
+	return value
+}
+
+func metadataDate(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.ReplaceAll(values[0], "_", "-")
+}
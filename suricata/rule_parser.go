@@ -0,0 +1,205 @@
+package suricata
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type Reference struct {
+	Type  string
+	Value string
+}
+
+// Rule is a Suricata or Snort IDS rule. Disabled rules are rules that are
+// commented out with a leading "#".
+type Rule struct {
+	Action   string
+	Protocol string
+	Header   string
+	Enabled  bool
+	LineNo   int
+
+	Msg        string
+	GID        string              `json:",omitempty"`
+	SID        string              `json:",omitempty"`
+	Rev        string              `json:",omitempty"`
+	Classtype  string              `json:",omitempty"`
+	Priority   string              `json:",omitempty"`
+	References []Reference         `json:",omitempty"`
+	Metadata   map[string][]string `json:",omitempty"`
+}
+
+var actionPattern = regexp.MustCompile(`^(alert|drop|reject|rejectsrc|rejectdst|rejectboth|pass|log|sdrop|activate|dynamic)\s+\S+\s+.*\(`)
+
+// ParseRules parses a rules file line by line. Lines ending with a backslash
+// are joined with the following line, and commented out rules are returned
+// with Enabled set to false. Comments that are not rules are skipped.
+// Enabled rules that fail to parse are skipped as well, and the rules of the
+// other lines are returned together with an error listing every bad line.
+func ParseRules(input []byte) ([]Rule, error) {
+	var rules []Rule
+	var errs []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(input))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var statement strings.Builder
+	lineNo, startLineNo := 0, 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+
+		if statement.Len() == 0 {
+			startLineNo = lineNo
+		}
+
+		if strings.HasSuffix(line, "\\") {
+			statement.WriteString(strings.TrimSuffix(line, "\\"))
+			continue
+		}
+		statement.WriteString(line)
+
+		text := strings.TrimSpace(statement.String())
+		statement.Reset()
+
+		if text == "" {
+			continue
+		}
+
+		enabled := true
+		if strings.HasPrefix(text, "#") {
+			enabled = false
+			text = strings.TrimSpace(strings.TrimLeft(text, "#"))
+		}
+
+		if !actionPattern.MatchString(text) {
+			if enabled {
+				errs = append(errs, fmt.Sprintf("line %d: invalid rule", startLineNo))
+			}
+			continue
+		}
+
+		rule, err := ParseRule(text)
+		if err != nil {
+			if enabled {
+				errs = append(errs, fmt.Sprintf("line %d: %v", startLineNo, err))
+			}
+			continue
+		}
+
+		rule.Enabled = enabled
+		rule.LineNo = startLineNo
+		rules = append(rules, rule)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return rules, err
+	}
+	if len(errs) > 0 {
+		return rules, errors.New(strings.Join(errs, "; "))
+	}
+	return rules, nil
+}
+
+// ParseRule parses a single rule written on one line.
+func ParseRule(text string) (Rule, error) {
+	rule := Rule{Enabled: true}
+
+	open := strings.Index(text, "(")
+	end := strings.LastIndex(text, ")")
+	if open < 0 || end < open {
+		return rule, fmt.Errorf("missing rule options")
+	}
+
+	header := strings.Fields(text[:open])
+	if len(header) < 2 {
+		return rule, fmt.Errorf("invalid rule header")
+	}
+	rule.Action = header[0]
+	rule.Protocol = header[1]
+	rule.Header = strings.Join(header[1:], " ")
+
+	for _, option := range splitOptions(text[open+1 : end]) {
+		key, value, _ := strings.Cut(option, ":")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "msg":
+			rule.Msg = unquote(value)
+		case "gid":
+			rule.GID = value
+		case "sid":
+			rule.SID = value
+		case "rev":
+			rule.Rev = value
+		case "classtype":
+			rule.Classtype = value
+		case "priority":
+			rule.Priority = value
+		case "reference":
+			referenceType, referenceValue, _ := strings.Cut(value, ",")
+			rule.References = append(rule.References, Reference{
+				Type:  strings.TrimSpace(referenceType),
+				Value: strings.TrimSpace(referenceValue),
+			})
+		case "metadata":
+			if rule.Metadata == nil {
+				rule.Metadata = make(map[string][]string)
+			}
+			for _, entry := range strings.Split(value, ",") {
+				entryKey, entryValue, _ := strings.Cut(strings.TrimSpace(entry), " ")
+				if entryKey != "" {
+					rule.Metadata[entryKey] = append(rule.Metadata[entryKey], strings.TrimSpace(entryValue))
+				}
+			}
+		}
+	}
+
+	if rule.SID == "" {
+		return rule, fmt.Errorf("missing sid option")
+	}
+
+	return rule, nil
+}
+
+// splitOptions splits the rule body on semicolons that are neither escaped
+// nor inside a quoted string.
+func splitOptions(body string) []string {
+	var options []string
+	var current strings.Builder
+	inQuotes, escaped := false, false
+
+	for _, c := range body {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == ';' && !inQuotes:
+			if option := strings.TrimSpace(current.String()); option != "" {
+				options = append(options, option)
+			}
+			current.Reset()
+			continue
+		}
+		current.WriteRune(c)
+	}
+
+	if option := strings.TrimSpace(current.String()); option != "" {
+		options = append(options, option)
+	}
+
+	return options
+}
+
+func unquote(value string) string {
+	value = strings.TrimSuffix(strings.TrimPrefix(value, `"`), `"`)
+	return strings.NewReplacer(`\"`, `"`, `\;`, `;`, `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,134 @@
+package suricata_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/mtnmunuklu/analyze-tags/suricata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRules(t *testing.T) {
+	err := filepath.Walk("./data/rules/", func(path string, info os.FileInfo, err error) error {
+		if !strings.HasSuffix(path, ".rules") {
+			return nil
+		}
+
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".rules"), func(t *testing.T) {
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed reading test input: %v", err)
+			}
+
+			_, err = suricata.ParseRules(contents)
+			if err != nil {
+				t.Fatalf("error parsing rules: %v", err)
+			}
+
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseRulesFields(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/emerging-malware.rules")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := suricata.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	if !assert.Len(t, rules, 4) {
+		return
+	}
+
+	assert.Equal(t, "ET MALWARE Win32/Agent Tesla Exfil via HTTP POST", rules[0].Msg)
+	assert.Equal(t, "alert", rules[0].Action)
+	assert.Equal(t, "http", rules[0].Protocol)
+	assert.Equal(t, "2030001", rules[0].SID)
+	assert.Equal(t, 6, rules[0].LineNo)
+	assert.Equal(t, []string{
+		"classtype:trojan-activity",
+		"mitre_tactic_id:TA0010",
+		"mitre_tactic_name:Exfiltration",
+		"mitre_technique_id:T1041",
+		"mitre_technique_name:Exfiltration_Over_C2_Channel",
+		"AgentTesla",
+	}, rules[0].Tags())
+
+	assert.Equal(t, `ET MALWARE Observed DNS Query to Known "Cobalt Strike" Domain; beacon`, rules[1].Msg)
+	assert.Equal(t, 8, rules[1].LineNo)
+	assert.Equal(t, []string{"classtype:command-and-control", "mitre_tactic_id:TA0011", "mitre_technique_id:T1071"}, rules[1].Tags())
+
+	assert.False(t, rules[2].Enabled)
+	assert.Equal(t, []string{"classtype:attempted-admin", "cve:CVE-2017-0144"}, rules[2].Tags())
+
+	assert.True(t, rules[3].Enabled)
+	assert.Equal(t, "2", rules[3].Priority)
+	assert.Equal(t, []string{"classtype:bad-unknown", "cve:CVE-1999-0001"}, rules[3].Tags())
+}
+
+func TestParseRulesInvalid(t *testing.T) {
+	_, err := suricata.ParseRules([]byte("alert tcp any any -> any any (msg:\"no sid\";)\n"))
+	assert.EqualError(t, err, "line 1: missing sid option")
+
+	_, err = suricata.ParseRules([]byte("\nnot a rule\n"))
+	assert.EqualError(t, err, "line 2: invalid rule")
+}
+
+func TestParseRulesPartial(t *testing.T) {
+	rules, err := suricata.ParseRules([]byte(`alert dns any any -> any any (msg:"first"; sid:1;)
+alert tcp any any -> any any (msg:"no sid";)
+# a comment
+not a rule
+alert dns any any -> any any (msg:"last"; sid:2;)
+`))
+	assert.EqualError(t, err, "line 2: missing sid option; line 4: invalid rule")
+	if assert.Len(t, rules, 2) {
+		assert.Equal(t, "first", rules[0].Msg)
+		assert.Equal(t, "last", rules[1].Msg)
+		assert.Equal(t, 5, rules[1].LineNo)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/emerging-malware.rules")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := suricata.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	normalized := rules[0].Normalize("emerging-malware.rules", 0)
+	assert.Equal(t, model.Suricata, normalized.Format)
+	assert.Equal(t, "2030001", normalized.ID)
+	assert.Equal(t, "Major", normalized.Severity)
+	assert.Equal(t, "2020-04-27", normalized.Date)
+	assert.Equal(t, "2020-05-12", normalized.Modified)
+	assert.Equal(t, []string{"Perimeter"}, normalized.Field("deployment"))
+	assert.Equal(t, []string{"true"}, normalized.Field("enabled"))
+}
+
+func TestNormalizeGID(t *testing.T) {
+	rules, err := suricata.ParseRules([]byte(`alert tcp any any -> any any (msg:"default"; gid:1; sid:2;)
+alert tcp any any -> any any (msg:"preprocessor"; gid:119; sid:2;)
+`))
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	assert.Equal(t, "2", rules[0].Normalize("local.rules", 0).Key())
+	assert.Equal(t, "119:2", rules[1].Normalize("local.rules", 1).Key())
+}
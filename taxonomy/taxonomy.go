@@ -0,0 +1,217 @@
+package taxonomy
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"gopkg.in/yaml.v3"
+)
+
+// Case is the case folding applied to canonical tags.
+type Case string
+
+const (
+	Preserve Case = "preserve"
+	Lower    Case = "lower"
+	Upper    Case = "upper"
+)
+
+// Taxonomy describes how the tags of every format are rewritten into a
+// canonical vocabulary so that charts and workbooks count them together.
+// Each tag goes through the rewrites, the namespace aliases, the tag aliases
+// and the case folding, in that order.
+type Taxonomy struct {
+	// Case folds every tag outside a namespace with its own case folding.
+	// Tags keep their case when it is empty.
+	Case Case `yaml:"case"`
+
+	// Namespaces are the known tag namespaces such as attack or cve.
+	Namespaces []Namespace `yaml:"namespaces"`
+
+	// Aliases map tags to their canonical form, e.g. Execution to
+	// attack.execution. Tags are matched without regard to case.
+	Aliases map[string]string `yaml:"aliases"`
+
+	// Rewrites are regular expression replacements applied in order. A tag
+	// rewritten to an empty string is removed.
+	Rewrites []Rewrite `yaml:"rewrites"`
+
+	aliases map[string]string
+}
+
+// Namespace is a tag namespace. Tags starting with one of the aliases, e.g.
+// mitre: or mitre_attack:, are moved to the namespace, e.g. attack.T1059.
+type Namespace struct {
+	Name string `yaml:"name"`
+
+	// Separator separates the namespace from the tag value, ":" by default.
+	Separator string   `yaml:"separator"`
+	Aliases   []string `yaml:"aliases"`
+	Case      Case     `yaml:"case"`
+}
+
+type Rewrite struct {
+	Match   string `yaml:"match"`
+	Replace string `yaml:"replace"`
+
+	pattern *regexp.Regexp
+}
+
+// Prefix returns the namespace name followed by its separator.
+func (n Namespace) Prefix() string {
+	if n.Separator == "" {
+		return n.Name + ":"
+	}
+	return n.Name + n.Separator
+}
+
+// Load reads a taxonomy from a local YAML file.
+func Load(path string) (*Taxonomy, error) {
+	input, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(input)
+}
+
+// Parse reads a taxonomy from YAML and checks its case foldings and
+// regular expressions.
+func Parse(input []byte) (*Taxonomy, error) {
+	t := &Taxonomy{}
+	if err := yaml.Unmarshal(input, t); err != nil {
+		return nil, err
+	}
+
+	if err := checkCase(t.Case); err != nil {
+		return nil, err
+	}
+
+	for _, namespace := range t.Namespaces {
+		if namespace.Name == "" {
+			return nil, fmt.Errorf("namespace without a name")
+		}
+		if err := checkCase(namespace.Case); err != nil {
+			return nil, fmt.Errorf("namespace %s: %v", namespace.Name, err)
+		}
+	}
+
+	for i := range t.Rewrites {
+		pattern, err := regexp.Compile(t.Rewrites[i].Match)
+		if err != nil {
+			return nil, fmt.Errorf("rewrite %d: %v", i+1, err)
+		}
+		t.Rewrites[i].pattern = pattern
+	}
+
+	t.aliases = make(map[string]string, len(t.Aliases))
+	for alias, tag := range t.Aliases {
+		t.aliases[strings.ToLower(alias)] = tag
+	}
+
+	return t, nil
+}
+
+func checkCase(c Case) error {
+	switch c {
+	case "", Preserve, Lower, Upper:
+		return nil
+	default:
+		return fmt.Errorf("unsupported case folding: %s", c)
+	}
+}
+
+// Namespace returns the namespace of a canonical tag.
+func (t *Taxonomy) Namespace(tag string) (Namespace, bool) {
+	for _, namespace := range t.Namespaces {
+		if hasPrefixFold(tag, namespace.Prefix()) {
+			return namespace, true
+		}
+	}
+	return Namespace{}, false
+}
+
+// Tag returns the canonical form of a tag, or an empty string when a rewrite
+// removes it.
+func (t *Taxonomy) Tag(tag string) string {
+	tag = strings.TrimSpace(tag)
+
+	for _, rewrite := range t.Rewrites {
+		tag = rewrite.pattern.ReplaceAllString(tag, rewrite.Replace)
+	}
+	if tag == "" {
+		return ""
+	}
+
+	tag = t.moveToNamespace(tag)
+
+	if alias, ok := t.aliases[strings.ToLower(tag)]; ok {
+		tag = alias
+	}
+
+	namespace, ok := t.Namespace(tag)
+	if !ok {
+		return fold(tag, t.Case)
+	}
+
+	// The namespace is spelled as declared whatever the case folding of its
+	// values.
+	c := t.Case
+	if namespace.Case != "" {
+		c = namespace.Case
+	}
+	return namespace.Prefix() + fold(tag[len(namespace.Prefix()):], c)
+}
+
+func fold(s string, c Case) string {
+	switch c {
+	case Lower:
+		return strings.ToLower(s)
+	case Upper:
+		return strings.ToUpper(s)
+	default:
+		return s
+	}
+}
+
+func (t *Taxonomy) moveToNamespace(tag string) string {
+	for _, namespace := range t.Namespaces {
+		for _, alias := range namespace.Aliases {
+			if hasPrefixFold(tag, alias) && len(tag) > len(alias) {
+				return namespace.Prefix() + tag[len(alias):]
+			}
+		}
+	}
+	return tag
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// Tags returns the canonical form of a tag list without the removed tags and
+// the duplicates the rewriting creates.
+func (t *Taxonomy) Tags(tags []string) []string {
+	var canonical []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = t.Tag(tag)
+		if tag != "" && !seen[tag] {
+			canonical = append(canonical, tag)
+			seen[tag] = true
+		}
+	}
+	return canonical
+}
+
+// Apply returns the rules with their tags in canonical form.
+func (t *Taxonomy) Apply(rules []model.Rule) []model.Rule {
+	applied := make([]model.Rule, len(rules))
+	for i, rule := range rules {
+		rule.Tags = t.Tags(rule.Tags)
+		applied[i] = rule
+	}
+	return applied
+}
@@ -0,0 +1,87 @@
+package taxonomy_test
+
+import (
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/mtnmunuklu/analyze-tags/taxonomy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	tax, err := taxonomy.Load("./data/taxonomy.yml")
+	if err != nil {
+		t.Fatalf("error loading taxonomy: %v", err)
+	}
+
+	tests := map[string]string{
+		"attack.t1059":                        "attack.t1059",
+		"T1059":                               "attack.t1059",
+		"t1059/001":                           "attack.t1059.001",
+		"mitre:T1110.001":                     "attack.t1110.001",
+		"mitre_execution":                     "attack.execution",
+		"Execution":                           "attack.execution",
+		"ATTACK.Execution":                    "attack.execution",
+		"credential access":                   "attack.credential_access",
+		"cve:cve-2021-44228":                  "cve:CVE-2021-44228",
+		"CVE:cve-2021-44228":                  "cve:CVE-2021-44228",
+		" container ":                         "container",
+		"maturity_stable":                     "",
+		"group:authentication":                "group:authentication",
+		"mitre_attack_id:T1059":               "attack.t1059",
+		"mitre_attack_technique_id:T1059/001": "attack.t1059.001",
+		"mitre_attack_tactic:Defense Evasion": "attack.defense_evasion",
+		"mitre_tactic_name:Exfiltration":      "attack.exfiltration",
+		"mitre_tactic_id:TA0010":              "mitre_tactic_id:TA0010",
+		"mitre_credential_access":             "attack.credential_access",
+	}
+
+	for tag, expected := range tests {
+		assert.Equal(t, expected, tax.Tag(tag), tag)
+	}
+
+	assert.Equal(t,
+		[]string{"attack.execution", "attack.t1059", "container"},
+		tax.Tags([]string{"maturity_stable", "mitre_execution", "Execution", "T1059", "attack.t1059", "container"}))
+
+	namespace, ok := tax.Namespace("attack.t1059")
+	if assert.True(t, ok) {
+		assert.Equal(t, "attack.", namespace.Prefix())
+	}
+	_, ok = tax.Namespace("container")
+	assert.False(t, ok)
+
+	rules := []model.Rule{{Title: "Rule1", Tags: []string{"T1059", "mitre_execution"}}}
+	applied := tax.Apply(rules)
+	assert.Equal(t, []string{"attack.t1059", "attack.execution"}, applied[0].Tags)
+	assert.Equal(t, []string{"T1059", "mitre_execution"}, rules[0].Tags)
+}
+
+func TestCaseFolding(t *testing.T) {
+	tax, err := taxonomy.Parse([]byte(`
+case: lower
+namespaces:
+  - name: cve
+    case: preserve
+`))
+	if err != nil {
+		t.Fatalf("error parsing taxonomy: %v", err)
+	}
+
+	assert.Equal(t, "windows", tax.Tag("Windows"))
+	assert.Equal(t, "cve:CVE-2021-44228", tax.Tag("CVE:CVE-2021-44228"))
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		"case: title":                          "unsupported case folding: title",
+		"namespaces: [{case: lower}]":          "namespace without a name",
+		"namespaces: [{name: a, case: mixed}]": "namespace a: unsupported case folding: mixed",
+		"rewrites: [{match: '('}]":             "rewrite 1: error parsing regexp: missing closing ): `(`",
+	}
+
+	for input, expected := range tests {
+		_, err := taxonomy.Parse([]byte(input))
+		assert.EqualError(t, err, expected, input)
+	}
+}
@@ -0,0 +1,66 @@
+package wazuh
+
+import (
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+// Compliance namespaces recognised in Wazuh group names, e.g.
+// pci_dss_10.2.4 becomes the tag pci_dss:10.2.4.
+var complianceNamespaces = []string{
+	"pci_dss",
+	"hipaa",
+	"nist_800_53",
+	"gdpr",
+	"gpg13",
+	"tsc",
+}
+
+// Tags returns the rule groups and MITRE ids as namespaced tags: group,
+// mitre and one namespace per compliance framework.
+func (r Rule) Tags() []string {
+	var tags []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		if !seen[tag] {
+			tags = append(tags, tag)
+			seen[tag] = true
+		}
+	}
+
+	for _, group := range r.Groups {
+		add(groupTag(group))
+	}
+	for _, id := range r.MITRE {
+		add("mitre:" + id)
+	}
+
+	return tags
+}
+
+func groupTag(group string) string {
+	for _, namespace := range complianceNamespaces {
+		if strings.HasPrefix(group, namespace+"_") {
+			return namespace + ":" + strings.TrimPrefix(group, namespace+"_")
+		}
+	}
+	return "group:" + group
+}
+
+func (r Rule) Normalize(path string, index int) model.Rule {
+	rule := model.Rule{
+		Format:   model.Wazuh,
+		Path:     path,
+		Index:    index,
+		ID:       r.ID,
+		Title:    r.Description,
+		Tags:     r.Tags(),
+		Severity: r.Level,
+	}
+
+	rule.AddMetadata("level", r.Level)
+	rule.AddMetadata("if_sid", r.IfSID...)
+
+	return rule
+}
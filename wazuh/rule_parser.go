@@ -0,0 +1,111 @@
+package wazuh
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Rule is a Wazuh or OSSEC rule. Groups holds the names of the enclosing
+// <group> element followed by the rule's own <group> entries.
+type Rule struct {
+	ID          string
+	Level       string
+	Description string
+	IfSID       []string `json:",omitempty"`
+	Groups      []string `json:",omitempty"`
+	MITRE       []string `json:",omitempty"`
+}
+
+type xmlGroup struct {
+	Name  string    `xml:"name,attr"`
+	Rules []xmlRule `xml:"rule"`
+}
+
+type xmlRule struct {
+	ID          string   `xml:"id,attr"`
+	Level       string   `xml:"level,attr"`
+	Description string   `xml:"description"`
+	IfSID       []string `xml:"if_sid"`
+	Groups      []string `xml:"group"`
+	MITRE       []string `xml:"mitre>id"`
+}
+
+// ParseRules parses a Wazuh rules file. Rules files usually contain several
+// top-level <group> elements, so the input is not required to be a single
+// well-formed XML document.
+func ParseRules(input []byte) ([]Rule, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(input))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var rules []Rule
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rules, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if start.Name.Local != "group" {
+			if err := decoder.Skip(); err != nil {
+				return rules, err
+			}
+			continue
+		}
+
+		group := xmlGroup{}
+		if err := decoder.DecodeElement(&group, &start); err != nil {
+			return rules, err
+		}
+
+		groupNames := splitList(group.Name)
+		for _, r := range group.Rules {
+			rule := Rule{
+				ID:          strings.TrimSpace(r.ID),
+				Level:       strings.TrimSpace(r.Level),
+				Description: strings.TrimSpace(r.Description),
+				MITRE:       trimAll(r.MITRE),
+				Groups:      append([]string(nil), groupNames...),
+			}
+			for _, ifSID := range r.IfSID {
+				rule.IfSID = append(rule.IfSID, splitList(ifSID)...)
+			}
+			for _, groups := range r.Groups {
+				rule.Groups = append(rule.Groups, splitList(groups)...)
+			}
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(c rune) bool { return c == ',' || c == ' ' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func trimAll(values []string) []string {
+	var trimmed []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			trimmed = append(trimmed, value)
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,83 @@
+package wazuh_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/mtnmunuklu/analyze-tags/wazuh"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRules(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/0095-sshd_rules.xml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := wazuh.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	if !assert.Len(t, rules, 4) {
+		return
+	}
+
+	assert.Equal(t, "5700", rules[0].ID)
+	assert.Equal(t, []string{"group:syslog", "group:sshd"}, rules[0].Tags())
+
+	rule := rules[1]
+	assert.Equal(t, "5710", rule.ID)
+	assert.Equal(t, "5", rule.Level)
+	assert.Equal(t, "sshd: Attempt to login using a non-existent user", rule.Description)
+	assert.Equal(t, []string{"5700"}, rule.IfSID)
+	assert.Equal(t, []string{"T1110.001", "T1021.004"}, rule.MITRE)
+	assert.Equal(t, []string{
+		"group:syslog",
+		"group:sshd",
+		"group:authentication_failed",
+		"gdpr:IV_35.7.d",
+		"gdpr:IV_32.2",
+		"hipaa:164.312.b",
+		"group:invalid_login",
+		"nist_800_53:AU.14",
+		"nist_800_53:AC.7",
+		"pci_dss:10.2.4",
+		"pci_dss:10.2.5",
+		"pci_dss:10.6.1",
+		"tsc:CC6.1",
+		"mitre:T1110.001",
+		"mitre:T1021.004",
+	}, rule.Tags())
+
+	assert.Equal(t, []string{"5700", "5716"}, rules[3].IfSID)
+}
+
+func TestNormalize(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/0095-sshd_rules.xml")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := wazuh.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	normalized := rules[2].Normalize("0095-sshd_rules.xml", 2)
+	assert.Equal(t, model.Wazuh, normalized.Format)
+	assert.Equal(t, "5712", normalized.Key())
+	assert.Equal(t, "10", normalized.Severity)
+	assert.Equal(t, []string{
+		"group:syslog",
+		"group:sshd",
+		"group:authentication_failures",
+		"gdpr:IV_35.7.d",
+		"hipaa:164.312.b",
+		"pci_dss:11.4",
+		"pci_dss:10.2.4",
+		"mitre:T1110",
+	}, normalized.Tags)
+	assert.Equal(t, []string{"10"}, normalized.Field("level"))
+}
@@ -0,0 +1,45 @@
+package yara
+
+import "github.com/mtnmunuklu/analyze-tags/model"
+
+// DefaultMetaTagKeys lists the meta keys that commonly carry ATT&CK
+// techniques or categories in public rule sets.
+var DefaultMetaTagKeys = []string{
+	"tags",
+	"tag",
+	"mitre_attack",
+	"mitre_att",
+	"mitre_technique",
+	"mitre_tactic",
+	"attack",
+	"technique",
+	"tactic",
+	"category",
+}
+
+// MetaTags returns the values of the given meta keys split into individual
+// tags. Keys are matched case-insensitively and values are split on commas,
+// semicolons, pipes and whitespace, as YARA meta values are lists of ids
+// such as T1055 T1027.005.
+func (r Rule) MetaTags(keys []string) []string {
+	return model.MetaTags(r.Meta, keys, model.MetaTagOptions{})
+}
+
+// MergeMetaTags adds the tags found in the given meta keys to the rule's tag
+// set, skipping tags that are already present.
+func (r *Rule) MergeMetaTags(keys []string) {
+	seen := make(map[string]bool, len(r.Tags))
+	tags := append([]string(nil), r.Tags...)
+	for _, tag := range tags {
+		seen[tag] = true
+	}
+
+	for _, tag := range r.MetaTags(keys) {
+		if !seen[tag] {
+			tags = append(tags, tag)
+			seen[tag] = true
+		}
+	}
+
+	r.Tags = tags
+}
@@ -0,0 +1,40 @@
+package yara
+
+import "github.com/mtnmunuklu/analyze-tags/model"
+
+var severityKeys = []string{"severity", "threat_level", "score"}
+
+func (r Rule) Normalize(path string, index int) model.Rule {
+	rule := model.Rule{
+		Format:   model.Yara,
+		Path:     path,
+		Index:    index,
+		ID:       r.firstMeta([]string{"id", "uuid"}),
+		Title:    r.Identifier,
+		Tags:     r.Tags,
+		Severity: r.firstMeta(severityKeys),
+		Author:   r.Author,
+		Date:     r.Date,
+		Modified: r.Modified,
+	}
+
+	for key, values := range r.Meta {
+		rule.AddMetadata(key, values...)
+	}
+
+	rule.AddMetadata("module", r.Imports...)
+	rule.AddMetadata("modifier", r.Modifiers...)
+	for _, stringType := range []StringType{TextString, HexString, RegexpString} {
+		if r.Strings[stringType] > 0 {
+			rule.AddMetadata("string_type", string(stringType))
+		}
+	}
+	if r.Global {
+		rule.AddMetadata("scope", "global")
+	}
+	if r.Private {
+		rule.AddMetadata("scope", "private")
+	}
+
+	return rule
+}
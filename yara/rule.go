@@ -0,0 +1,213 @@
+package yara
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/VirusTotal/gyp/ast"
+)
+
+type StringType string
+
+const (
+	TextString   StringType = "text"
+	HexString    StringType = "hex"
+	RegexpString StringType = "regexp"
+)
+
+// Rule is a summary of a YARA rule built from the gyp AST. Well-known meta
+// keys are lifted into dedicated fields, every meta entry is kept in Meta
+// with its key lowercased.
+type Rule struct {
+	Identifier string
+	Tags       []string `json:",omitempty"`
+	Global     bool     `json:",omitempty"`
+	Private    bool     `json:",omitempty"`
+
+	Author      string              `json:",omitempty"`
+	Description string              `json:",omitempty"`
+	Date        string              `json:",omitempty"`
+	Modified    string              `json:",omitempty"`
+	Reference   []string            `json:",omitempty"`
+	Hashes      []string            `json:",omitempty"`
+	Meta        map[string][]string `json:",omitempty"`
+
+	Strings   map[StringType]int `json:",omitempty"`
+	Modifiers []string           `json:",omitempty"`
+
+	// Imports lists every module imported by the rule file, UsedModules
+	// the imported modules the condition of the rule refers to.
+	Imports     []string `json:",omitempty"`
+	UsedModules []string `json:",omitempty"`
+}
+
+var (
+	authorKeys      = []string{"author", "authors"}
+	descriptionKeys = []string{"description", "desc"}
+	dateKeys        = []string{"date", "creation_date", "created"}
+	modifiedKeys    = []string{"last_modified", "modified"}
+	referenceKeys   = []string{"reference", "references", "ref", "url", "link"}
+	hashKeys        = []string{"hash", "md5", "sha1", "sha256", "sample", "samples"}
+)
+
+func ParseRules(input []byte) ([]Rule, error) {
+	rs, err := ParseByte(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return Summarize(rs), nil
+}
+
+func Summarize(rs *ast.RuleSet) []Rule {
+	rules := make([]Rule, 0, len(rs.Rules))
+	for _, r := range rs.Rules {
+		rules = append(rules, NewRule(rs, r))
+	}
+	return rules
+}
+
+func NewRule(rs *ast.RuleSet, r *ast.Rule) Rule {
+	rule := Rule{
+		Identifier: r.Identifier,
+		Tags:       r.Tags,
+		Global:     r.Global,
+		Private:    r.Private,
+		Meta:       make(map[string][]string),
+		Strings:    make(map[StringType]int),
+	}
+
+	for _, meta := range r.Meta {
+		key := strings.ToLower(meta.Key)
+		rule.Meta[key] = append(rule.Meta[key], metaValue(meta))
+	}
+
+	rule.Author = rule.firstMeta(authorKeys)
+	rule.Description = rule.firstMeta(descriptionKeys)
+	rule.Date = rule.firstMeta(dateKeys)
+	rule.Modified = rule.firstMeta(modifiedKeys)
+	rule.Reference = rule.allMeta(referenceKeys)
+	rule.Hashes = rule.allMeta(hashKeys)
+
+	modifiers := make(map[string]bool)
+	for _, s := range r.Strings {
+		switch s := s.(type) {
+		case *ast.TextString:
+			rule.Strings[TextString]++
+			addModifiers(modifiers, map[string]bool{
+				"ascii":      s.ASCII,
+				"wide":       s.Wide,
+				"nocase":     s.Nocase,
+				"fullword":   s.Fullword,
+				"private":    s.Private,
+				"base64":     s.Base64,
+				"base64wide": s.Base64Wide,
+				"xor":        s.Xor,
+			})
+		case *ast.HexString:
+			rule.Strings[HexString]++
+			addModifiers(modifiers, map[string]bool{
+				"private": s.Private,
+			})
+		case *ast.RegexpString:
+			rule.Strings[RegexpString]++
+			addModifiers(modifiers, map[string]bool{
+				"ascii":    s.ASCII,
+				"wide":     s.Wide,
+				"nocase":   s.Nocase,
+				"fullword": s.Fullword,
+				"private":  s.Private,
+			})
+		}
+	}
+	rule.Modifiers = sortedKeys(modifiers)
+
+	rule.Imports = rs.Imports
+	if r.Condition != nil {
+		rule.UsedModules = usedModules(rs.Imports, r.Condition)
+	}
+
+	return rule
+}
+
+func (r Rule) firstMeta(keys []string) string {
+	for _, key := range keys {
+		if values := r.Meta[key]; len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+func (r Rule) allMeta(keys []string) []string {
+	var values []string
+	for key, metaValues := range r.Meta {
+		for _, k := range keys {
+			if strings.HasPrefix(key, k) && isDigits(key[len(k):]) {
+				values = append(values, metaValues...)
+				break
+			}
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+func metaValue(meta *ast.Meta) string {
+	if s, ok := meta.Value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(meta.Value)
+}
+
+func addModifiers(modifiers map[string]bool, used map[string]bool) {
+	for modifier, ok := range used {
+		if ok {
+			modifiers[modifier] = true
+		}
+	}
+}
+
+type moduleVisitor struct {
+	imports map[string]bool
+	used    map[string]bool
+}
+
+func (v *moduleVisitor) PreOrderVisit(node ast.Node) {
+	if identifier, ok := node.(*ast.Identifier); ok && v.imports[identifier.Identifier] {
+		v.used[identifier.Identifier] = true
+	}
+}
+
+func usedModules(imports []string, condition ast.Expression) []string {
+	visitor := &moduleVisitor{
+		imports: make(map[string]bool),
+		used:    make(map[string]bool),
+	}
+	for _, module := range imports {
+		visitor.imports[module] = true
+	}
+
+	ast.DepthFirstSearch(condition, visitor)
+
+	return sortedKeys(visitor.used)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
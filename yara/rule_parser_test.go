@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/mtnmunuklu/analyze-tags/model"
 	"github.com/mtnmunuklu/analyze-tags/yara"
 	"github.com/stretchr/testify/assert"
 )
@@ -523,3 +524,118 @@ func TestWriteSource(t *testing.T) {
 		}
 	}
 }
+
+func TestParseRules(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/sus_nsis_tampered_signature.yar")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := yara.ParseRules(contents)
+	if !assert.NoError(t, err) || !assert.Len(t, rules, 1) {
+		return
+	}
+
+	rule := rules[0]
+	assert.Equal(t, "sus_nsis_tampered_signature", rule.Identifier)
+	assert.Equal(t, "Maxime THIEBAUT (@0xThiebaut)", rule.Author)
+	assert.Equal(t, "2023-06-01", rule.Date)
+	assert.Equal(t, []string{"https://thedfirreport.com/"}, rule.Reference)
+	assert.Equal(t, []string{"121a1f64fff22c4bfcef3f11a23956ed403cdeb9bdb803f9c42763087bd6d94e"}, rule.Hashes)
+	assert.Equal(t, []string{"T1027.005"}, rule.Meta["mitre_att"])
+	assert.Equal(t, map[yara.StringType]int{yara.TextString: 4, yara.HexString: 11}, rule.Strings)
+	assert.Equal(t, []string{"fullword"}, rule.Modifiers)
+	assert.Empty(t, rule.Imports)
+	assert.Empty(t, rule.UsedModules)
+}
+
+func TestParseRulesSummary(t *testing.T) {
+	rules, err := yara.ParseRules([]byte(`
+import "pe"
+import "dotnet"
+import "math"
+
+global private rule foo : bar {
+  meta:
+    Author = "someone"
+    hash1 = "aaa"
+    hash2 = "bbb"
+    weight = 3
+    last_modified = "2024-02-01"
+  strings:
+    $a = "foo" wide nocase
+    $b = /ba[rz]/ ascii
+    $c = { 01 02 }
+  condition:
+    pe.is_pe and dotnet.number_of_resources > 0 and any of them
+}
+`))
+	if !assert.NoError(t, err) || !assert.Len(t, rules, 1) {
+		return
+	}
+
+	rule := rules[0]
+	assert.True(t, rule.Global)
+	assert.True(t, rule.Private)
+	assert.Equal(t, "someone", rule.Author)
+	assert.Empty(t, rule.Date)
+	assert.Equal(t, "2024-02-01", rule.Modified)
+	assert.Equal(t, []string{"aaa", "bbb"}, rule.Hashes)
+	assert.Equal(t, []string{"3"}, rule.Meta["weight"])
+	assert.Equal(t, map[yara.StringType]int{yara.TextString: 1, yara.RegexpString: 1, yara.HexString: 1}, rule.Strings)
+	assert.Equal(t, []string{"ascii", "nocase", "wide"}, rule.Modifiers)
+	assert.Equal(t, []string{"pe", "dotnet", "math"}, rule.Imports)
+	assert.Equal(t, []string{"dotnet", "pe"}, rule.UsedModules)
+}
+
+func TestMergeMetaTags(t *testing.T) {
+	rules, err := yara.ParseRules([]byte(`
+rule foo : loader {
+  meta:
+    tags = "apt, loader"
+    MITRE_ATTACK = "T1055 T1027.005"
+    category = "MALWARE"
+  condition:
+    true
+}
+`))
+	if !assert.NoError(t, err) || !assert.Len(t, rules, 1) {
+		return
+	}
+
+	rule := rules[0]
+	assert.Equal(t, []string{"T1055", "T1027.005"}, rule.MetaTags([]string{"mitre_attack"}))
+
+	rule.MergeMetaTags(yara.DefaultMetaTagKeys)
+	assert.Equal(t, []string{"loader", "apt", "T1055", "T1027.005", "MALWARE"}, rule.Tags)
+	assert.Equal(t, []string{"loader"}, rules[0].Tags)
+}
+
+func TestNormalize(t *testing.T) {
+	rules, err := yara.ParseRules([]byte(`
+import "pe"
+
+rule foo : bar {
+  meta:
+    id = "abc"
+    author = "someone"
+    severity = "high"
+  strings:
+    $a = { 01 02 }
+  condition:
+    pe.is_pe and $a
+}
+`))
+	if !assert.NoError(t, err) || !assert.Len(t, rules, 1) {
+		return
+	}
+
+	normalized := rules[0].Normalize("foo.yar", 0)
+	assert.Equal(t, model.Yara, normalized.Format)
+	assert.Equal(t, "abc", normalized.ID)
+	assert.Equal(t, "foo", normalized.Title)
+	assert.Equal(t, "high", normalized.Severity)
+	assert.Equal(t, "someone", normalized.Author)
+	assert.Equal(t, []string{"pe"}, normalized.Field("module"))
+	assert.Equal(t, []string{"hex"}, normalized.Field("string_type"))
+}
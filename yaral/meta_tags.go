@@ -0,0 +1,37 @@
+package yaral
+
+import "github.com/mtnmunuklu/analyze-tags/model"
+
+// DefaultMetaTagKeys lists the meta keys of the Chronicle community rules
+// that carry ATT&CK mappings or classify the rule.
+var DefaultMetaTagKeys = []string{
+	"tags",
+	"mitre_attack_tactic",
+	"mitre_attack_technique",
+	"mitre_attack_technique_id",
+	"technique",
+	"tactic",
+	"severity",
+	"priority",
+	"type",
+	"platform",
+	"data_source",
+}
+
+// MetaTags returns the values of the given meta keys as namespaced tags such
+// as severity:Low or mitre_attack_tactic:Defense Evasion. Values are split
+// on commas, semicolons and pipes but not on whitespace, as YARA-L meta
+// values are names rather than lists of ids. The values of the tags key are
+// used as they are.
+func (r Rule) MetaTags(keys []string) []string {
+	return model.MetaTags(r.Meta, keys, model.MetaTagOptions{Namespaced: true, KeepSpaces: true})
+}
+
+func (r Rule) firstMeta(keys ...string) string {
+	for _, key := range keys {
+		if values := r.Meta[key]; len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
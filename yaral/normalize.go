@@ -0,0 +1,36 @@
+package yaral
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+)
+
+func (r Rule) Normalize(path string, index int, keys []string) model.Rule {
+	rule := model.Rule{
+		Format:   model.YaraL,
+		Path:     path,
+		Index:    index,
+		ID:       r.firstMeta("id", "rule_id"),
+		Title:    r.Name,
+		Tags:     r.MetaTags(keys),
+		Severity: strings.ToLower(r.firstMeta("severity", "priority")),
+		Author:   r.firstMeta("author"),
+		Date:     r.firstMeta("date", "created"),
+		Modified: r.firstMeta("last_modified", "modified"),
+	}
+
+	for key, values := range r.Meta {
+		rule.AddMetadata(key, values...)
+	}
+
+	sections := make([]string, 0, len(r.Sections))
+	for section := range r.Sections {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	rule.AddMetadata("section", sections...)
+
+	return rule
+}
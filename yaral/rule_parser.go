@@ -0,0 +1,205 @@
+package yaral
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Rule is a Chronicle YARA-L 2.0 rule. Meta keys are lowercase and Sections
+// holds the raw text of the events, match, outcome, options and condition
+// sections, keyed by section name.
+type Rule struct {
+	Name     string
+	Meta     map[string][]string `json:",omitempty"`
+	Sections map[string]string   `json:",omitempty"`
+}
+
+var (
+	rulePattern    = regexp.MustCompile(`\brule\s+([A-Za-z_]\w*)\s*\{`)
+	sectionPattern = regexp.MustCompile(`(?m)^[ \t]*(meta|events|match|outcome|options|condition)[ \t]*:`)
+)
+
+// requiredSections are the sections every YARA-L 2.0 rule must have.
+var requiredSections = []string{"events", "condition"}
+
+// ParseRules parses every rule of a YARA-L file. Comments are ignored, and
+// braces inside strings and regular expressions do not end a rule.
+func ParseRules(input []byte) ([]Rule, error) {
+	code, literal := stripComments(input)
+
+	var rules []Rule
+	for pos := 0; pos < len(code); {
+		loc := rulePattern.FindSubmatchIndex(code[pos:])
+		if loc == nil {
+			break
+		}
+		start, open := pos+loc[0], pos+loc[1]-1
+		if literal[start] {
+			pos = start + 1
+			continue
+		}
+
+		name := string(code[pos+loc[2] : pos+loc[3]])
+		end := closingBrace(code, literal, open)
+		if end < 0 {
+			return rules, fmt.Errorf("rule %s: missing closing brace", name)
+		}
+
+		rule, err := parseBody(name, string(code[open+1:end]))
+		if err != nil {
+			return rules, err
+		}
+
+		rules = append(rules, rule)
+		pos = end + 1
+	}
+
+	return rules, nil
+}
+
+func parseBody(name, body string) (Rule, error) {
+	rule := Rule{
+		Name:     name,
+		Meta:     make(map[string][]string),
+		Sections: make(map[string]string),
+	}
+
+	labels := sectionPattern.FindAllStringSubmatchIndex(body, -1)
+	for i, label := range labels {
+		end := len(body)
+		if i+1 < len(labels) {
+			end = labels[i+1][0]
+		}
+
+		section := body[label[2]:label[3]]
+		text := strings.TrimSpace(body[label[1]:end])
+		if section == "meta" {
+			parseMeta(rule.Meta, text)
+			continue
+		}
+		rule.Sections[section] = text
+	}
+
+	for _, section := range requiredSections {
+		if rule.Sections[section] == "" {
+			return rule, fmt.Errorf("rule %s: missing %s section", name, section)
+		}
+	}
+
+	return rule, nil
+}
+
+func parseMeta(meta map[string][]string, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, `"`) {
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				value = unquoted
+			} else {
+				value = strings.TrimSuffix(strings.TrimPrefix(value, `"`), `"`)
+			}
+		}
+
+		if key != "" && value != "" {
+			meta[key] = append(meta[key], value)
+		}
+	}
+}
+
+// stripComments blanks out // and /* */ comments, keeping line breaks, and
+// returns a mask of the bytes that are part of a string or regular
+// expression literal. A slash starts a regular expression when it follows
+// an operator, an opening parenthesis or a comma.
+func stripComments(input []byte) ([]byte, []bool) {
+	code := append([]byte(nil), input...)
+	literal := make([]bool, len(code))
+
+	var previous byte
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case c == '/' && i+1 < len(code) && code[i+1] == '/':
+			for ; i < len(code) && code[i] != '\n'; i++ {
+				code[i] = ' '
+			}
+			continue
+		case c == '/' && i+1 < len(code) && code[i+1] == '*':
+			for ; i < len(code) && !(code[i] == '*' && i+1 < len(code) && code[i+1] == '/'); i++ {
+				if code[i] != '\n' {
+					code[i] = ' '
+				}
+			}
+			if i < len(code) {
+				code[i] = ' '
+				if i+1 < len(code) {
+					i++
+					code[i] = ' '
+				}
+			}
+			continue
+		case c == '"' || c == '`' || (c == '/' && strings.IndexByte("=~(,!", previous) >= 0):
+			i = skipLiteral(code, literal, i)
+		}
+
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			previous = c
+		}
+	}
+
+	return code, literal
+}
+
+// skipLiteral marks the literal opened at start and returns the index of its
+// closing delimiter. Backtick strings are raw, and regular expressions end
+// at the end of the line when they are not closed.
+func skipLiteral(code []byte, literal []bool, start int) int {
+	delimiter := code[start]
+	literal[start] = true
+
+	i := start + 1
+	for ; i < len(code); i++ {
+		literal[i] = true
+		switch {
+		case code[i] == '\\' && delimiter != '`':
+			if i+1 < len(code) {
+				i++
+				literal[i] = true
+			}
+		case code[i] == delimiter:
+			return i
+		case code[i] == '\n' && delimiter == '/':
+			literal[i] = false
+			return i
+		}
+	}
+	return i
+}
+
+// closingBrace returns the index of the brace that closes the one at open,
+// or -1 when it is missing.
+func closingBrace(code []byte, literal []bool, open int) int {
+	depth := 0
+	for i := open; i < len(code); i++ {
+		if literal[i] {
+			continue
+		}
+		switch code[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,76 @@
+package yaral_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mtnmunuklu/analyze-tags/model"
+	"github.com/mtnmunuklu/analyze-tags/yaral"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRules(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/windows_process_rules.yaral")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := yaral.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	if !assert.Len(t, rules, 2) {
+		return
+	}
+
+	rule := rules[0]
+	assert.Equal(t, "suspicious_unusual_location_svchost_execution", rule.Name)
+	assert.Equal(t, []string{"Windows 'svchost' executed from an unusual location"}, rule.Meta["description"])
+	assert.Equal(t, "$process", rule.Sections["condition"])
+	assert.Equal(t, "$risk_score = max(35)", rule.Sections["outcome"])
+	assert.NotContains(t, rule.Sections, "match")
+
+	rule = rules[1]
+	assert.Equal(t, "excessive_failed_logins", rule.Name)
+	assert.Equal(t, "$user over 10m", rule.Sections["match"])
+	assert.Equal(t, "#login > 5", rule.Sections["condition"])
+	assert.Equal(t, []string{
+		"attack.t1110",
+		"attack.credential_access",
+		"mitre_attack_tactic:Credential Access",
+		"mitre_attack_technique:Brute Force",
+		"mitre_attack_technique_id:T1110",
+		"mitre_attack_technique_id:T1110.001",
+		"severity:Medium",
+	}, rule.MetaTags(yaral.DefaultMetaTagKeys))
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	_, err := yaral.ParseRules([]byte("rule no_events {\n  meta:\n    author = \"x\"\n  condition:\n    $e\n}\n"))
+	assert.EqualError(t, err, "rule no_events: missing events section")
+
+	_, err = yaral.ParseRules([]byte("rule unterminated {\n  events:\n    $e.metadata.event_type = \"}\"\n"))
+	assert.EqualError(t, err, "rule unterminated: missing closing brace")
+}
+
+func TestNormalize(t *testing.T) {
+	contents, err := os.ReadFile("./data/rules/windows_process_rules.yaral")
+	if err != nil {
+		t.Fatalf("failed reading test input: %v", err)
+	}
+
+	rules, err := yaral.ParseRules(contents)
+	if err != nil {
+		t.Fatalf("error parsing rules: %v", err)
+	}
+
+	normalized := rules[0].Normalize("rules.yaral", 0, []string{"mitre_attack_technique_id", "severity"})
+	assert.Equal(t, model.YaraL, normalized.Format)
+	assert.Equal(t, "suspicious_unusual_location_svchost_execution", normalized.Title)
+	assert.Equal(t, []string{"mitre_attack_technique_id:T1036.005", "severity:Low"}, normalized.Tags)
+	assert.Equal(t, "low", normalized.Severity)
+	assert.Equal(t, "Google Cloud Security", normalized.Author)
+	assert.Equal(t, []string{"condition", "events", "outcome"}, normalized.Field("section"))
+	assert.Equal(t, []string{"YL2.0"}, normalized.Field("yara_version"))
+}